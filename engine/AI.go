@@ -8,10 +8,8 @@ import (
 	"github.com/yaricom/goNEAT/v4/experiment/utils"
 	"github.com/yaricom/goNEAT/v4/neat"
 	"github.com/yaricom/goNEAT/v4/neat/genetics"
-	"github.com/yaricom/goNEAT/v4/neat/network"
 	"log"
 	"math"
-	"math/rand"
 	"os"
 	"sync"
 	"sync/atomic"
@@ -21,6 +19,8 @@ const CountGames = 50
 
 type AbaloneGenerationEvaluator struct {
 	OutputPath string
+	NewGame    func() GameState // starts a new game for each evaluation game
+	Augment    bool             // average the network output over the symmetries of the board
 }
 
 func (e *AbaloneGenerationEvaluator) GenerationEvaluate(ctx context.Context, pop *genetics.Population, epoch *experiment.Generation) error {
@@ -44,8 +44,12 @@ func (e *AbaloneGenerationEvaluator) GenerationEvaluate(ctx context.Context, pop
 		go func() {
 			defer wg.Done()
 
-			_, err := e.orgEvaluate(org, epoch)
+			_, err := e.orgEvaluate(ctx, org, epoch)
 			if err != nil {
+				// the experiment is stopped, its games are aborted
+				if ctx.Err() != nil {
+					return
+				}
 				panic(err)
 			}
 
@@ -69,6 +73,10 @@ func (e *AbaloneGenerationEvaluator) GenerationEvaluate(ctx context.Context, pop
 
 	wg.Wait()
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	log.Println(fmt.Sprintf("[Gen %d] Found new champion with fitness: %f", epoch.Id, epoch.Champion.Fitness))
 
 	if optPath, err := utils.WriteGenomePlain("abalone_champion", e.OutputPath, epoch.Champion, epoch); err != nil {
@@ -149,16 +157,16 @@ func writeGenerationCSV(outputPath string, epoch *experiment.Generation, average
 	return nil
 }
 
-func NewAbaloneGenerationEvaluator(outputPath string) experiment.GenerationEvaluator {
-	return &AbaloneGenerationEvaluator{OutputPath: outputPath}
+func NewAbaloneGenerationEvaluator(outputPath string, newGame func() GameState, augment bool) experiment.GenerationEvaluator {
+	return &AbaloneGenerationEvaluator{OutputPath: outputPath, NewGame: newGame, Augment: augment}
 }
 
-// orgEvaluate evaluates fitness of the provided organism
-func (e *AbaloneGenerationEvaluator) orgEvaluate(organism *genetics.Organism, epoch *experiment.Generation) (bool, error) {
+// orgEvaluate evaluates fitness of the provided organism, its games are aborted when ctx is done
+func (e *AbaloneGenerationEvaluator) orgEvaluate(ctx context.Context, organism *genetics.Organism, epoch *experiment.Generation) (bool, error) {
 	// evaluate the organism by running 100 games against random opponent
 	// fitness is the average score difference between the organism and the opponent
 
-	// INPUT: 9 cells, 2 possible states (1,2) = 18 input nodes
+	// INPUT: the features of the game state, see GameState.Features
 	// OUTPUT: 1 node for board evaluation
 
 	phenotype, err := organism.Phenotype()
@@ -166,79 +174,39 @@ func (e *AbaloneGenerationEvaluator) orgEvaluate(organism *genetics.Organism, ep
 		return false, err
 	}
 
-	netDepth, err := phenotype.MaxActivationDepthWithCap(0) // The max depth of the network to be activated
+	neatPlayer, err := NewNeatPlayer(phenotype)
 	if err != nil {
-		neat.WarnLog(fmt.Sprintf(
-			"Failed to estimate maximal depth of the network with loop:\n%s\nUsing default depth: %d",
-			organism.Genotype, netDepth))
-	}
-	neat.DebugLog(fmt.Sprintf("Network depth: %d for organism: %d\n", netDepth, organism.Genotype.Id))
-	if netDepth == 0 {
-		neat.DebugLog(fmt.Sprintf("ALERT: Network depth is ZERO for Genome: %s", organism.Genotype))
+		neat.DebugLog(fmt.Sprintf("ALERT: %s for Genome: %s", err, organism.Genotype))
 		return false, nil
 	}
+	neatPlayer.Augment = e.Augment
+	neat.DebugLog(fmt.Sprintf("Network depth: %d for organism: %d\n", neatPlayer.Depth, organism.Genotype.Id))
+
+	// player 1 is the organism, the other players are random opponents
+	players := []Player{neatPlayer}
+	for i := 1; i < MaxPlayers; i++ {
+		players = append(players, &RandomPlayer{})
+	}
 
 	totalScore := 0
 
 	for gameId := 0; gameId < CountGames; gameId++ {
 		//log.Println(fmt.Sprintf("[Gen %d][Org %d] Starting game %d", epoch.Id, organism.Genotype.Id, gameId))
-		game := NewGame(startingGrid)
-
-		for !game.IsOver() && game.Turn < 10 {
-			//log.Println(fmt.Sprintf("[Gen %d][Org %d] Game %d, turn %d", epoch.Id, organism.Genotype.Id, gameId, game.Turn))
-
-			possibleMoves := game.GetValidMoves()
-			if len(possibleMoves) == 0 {
-				game.Winner = 1
-			} else {
-
-				var move Move
-				if game.currentPlayer == 1 {
-					// player 1 is the organism
-
-					movePtr, err := e.predictSingleMove(phenotype, netDepth, *game)
-
-					if err != nil {
-						return false, err
-					}
-
-					move = *movePtr
-					//log.Println(fmt.Sprintf("[Gen %d][Org %d] Predicted move: %v", epoch.Id, organism.Genotype.Id, move))
+		game := e.NewGame()
 
-					err = game.Move(move)
-
-					if err != nil {
-						// invalid move, opponent wins
-						log.Println(fmt.Sprintf("[Gen %d][Org %d] Invalid move: %v", epoch.Id, organism.Genotype.Id, move))
-						game.Winner = 3
-						panic(fmt.Sprintf("Invalid move: %v", move))
-					}
-				} else {
-					// player 2 is the random opponent
-
-					// pick a random move
-					move = helpers.RandIn(possibleMoves)
-
-					err := game.Move(move)
-					if err != nil {
-						return false, err
-					}
-				}
-
-				//log.Println(fmt.Sprintf("Turn %d state after move %v:\n%s", game.Turn, move, game.Show()))
-			}
+		turn, err := PlayGame(ctx, game, players)
+		if err != nil {
+			return false, err
 		}
 
 		thisGameScore := 0
-		if game.Winner == 2 {
-			thisGameScore += 1000000 - int(game.Turn)
-		} else if game.Winner == 3 {
-			thisGameScore -= 1000000 + int(game.Turn)
+		if game.Winner() == 1 {
+			thisGameScore += 1000000 - turn
+		} else if game.Winner() > 1 {
+			thisGameScore -= 1000000 + turn
 		}
 
-		//log.Println(fmt.Sprintf("State after game %d:\n%s", gameId, game.Show()))
-
-		//log.Println(fmt.Sprintf("[Gen %d][Org %d] Finished game %d, score: %v after %d turns", epoch.Id, organism.Genotype.Id, gameId, thisGameScore, game.Turn))
+		//log.Println(fmt.Sprintf("[Gen %d][Org %d] Finished game %d, score: %v after %d turns", epoch.Id, organism.Genotype.Id, gameId, thisGameScore, turn))
 
 		totalScore += thisGameScore
 	}
@@ -260,79 +228,3 @@ func (e *AbaloneGenerationEvaluator) orgEvaluate(organism *genetics.Organism, ep
 
 	return false, nil
 }
-
-func (e *AbaloneGenerationEvaluator) predictSingleMove(phenotype *network.Network, netDepth int, game Game) (*Move, error) {
-	validMoves := game.GetValidMoves()
-
-	if len(validMoves) == 0 {
-		return nil, fmt.Errorf("no valid moves")
-	}
-
-	bestMoveScore := -1000000.0
-	var bestMove *Move
-
-	rand.Shuffle(len(validMoves), func(i, j int) {
-		validMoves[i], validMoves[j] = validMoves[j], validMoves[i]
-	})
-
-	for _, move := range validMoves {
-		nextState := game.Copy()
-		err := nextState.Move(move)
-
-		if err != nil {
-			return nil, err
-		}
-
-		var in []float64
-
-		// Set the input values
-		for y := int8(0); y < 3; y++ {
-			for x := int8(0); x < 3; x++ {
-				coord := Coord2D{x, y}
-				cellOwner := nextState.GetGrid(coord)
-				player1 := 0.0
-				player2 := 0.0
-
-				if cellOwner == 1 {
-					player1 = 1.0
-				} else if cellOwner == 2 {
-					player2 = 1.0
-				}
-
-				in = append(in, player1, player2)
-			}
-		}
-
-		if err = phenotype.LoadSensors(in); err != nil {
-			neat.ErrorLog(fmt.Sprintf("Failed to load sensors: %s", err))
-			return nil, err
-		}
-
-		// Use depth to ensure full relaxation
-		if success, err := phenotype.ForwardSteps(netDepth); err != nil || !success {
-			neat.ErrorLog(fmt.Sprintf("Failed to activate network: %s", err))
-			return nil, err
-		}
-
-		// Read output
-		score := phenotype.Outputs[0].Activation
-
-		//log.Println(fmt.Sprintf("Move: %v, score: %f", move, score))
-
-		if score > bestMoveScore || bestMove == nil {
-			bestMoveScore = score
-			bestMove = &move
-			//log.Println(fmt.Sprintf("New best move: %v, score: %f", move, score))
-		}
-
-		// Flush network for subsequent use
-		if _, err = phenotype.Flush(); err != nil {
-			neat.ErrorLog(fmt.Sprintf("Failed to flush network: %s", err))
-			return nil, err
-		}
-	}
-
-	//log.Println(fmt.Sprintf("Best move: %v, score: %f among %d valid moves", *bestMove, bestMoveScore, len(validMoves)))
-
-	return bestMove, nil
-}
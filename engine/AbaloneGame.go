@@ -3,43 +3,94 @@ package engine
 import (
 	"errors"
 	"fmt"
-	"log"
 )
 
 /**
  * Game is the main game class.
  * It contains the grid and the players.
  *
- * The grid is a 2D array of integers.
+ * The grid is a hexagon of integers.
  * Here is the meaning of the integers:
  * 0: empty
- * 1: player 1
- * 2: player 2
+ * 1 to Players: marble of that player
  *
  * The players are represented by their color.
  * 1: white
  * 2: black
+ * 3: blue, for the three players variant
+ *
+ * Players play in turn, from 1 to Players, then back to 1.
  *
  * Grid layout:
- * - the grid is a 3x3 square
+ * - the grid is a hexagon of 61 cells, BoardRadius cells from the center to each edge
+ * - cells are addressed with cube coordinates (Coord3D), X + Y + Z = 0
+ * - Z is the row, from -BoardRadius (top) to BoardRadius (bottom)
+ *
+ * Moves:
+ * - a line of 1 to MaxGroupSize marbles moves one cell along its own axis
+ * - it can push a strictly smaller line of enemy marbles (sumito), possibly of several opponents
+ * - a line of 2 to MaxGroupSize marbles can also move sideways (broadside) into empty cells
+ * - enemy marbles pushed out of the hexagon are ejected and scored
+ * - own marbles can only be pushed out with AllowSelfEjection, they are then scored by the next player
+ * - the first player to eject MarblesToWin enemy marbles wins
+ * - the game is a draw when a position occurs RepetitionsForDraw times, or after MaxTurns moves
+ *
+ * The values in capitals come from the Rules of the game.
  */
 
 type Game struct {
-	grid          [3][3]int8 // 0: empty, 1: player 1, 2: player 2
-	currentPlayer int8       // 1 or 2
-	Turn          int8
-	Winner        int8 // 0 if no Winner, 1 for tie, 2 or 3 if there is a Winner
+	grid          Grid                 // 0: empty, 1 to Players: player
+	currentPlayer int8                 // 1 to Players
+	score         [MaxPlayers + 1]int8 // enemy marbles ejected by each player, indexed by player
+	winner        int8                 // see Winner
+	cellsHash     uint64               // Zobrist hash of the marbles, see Hash
+	positions     map[uint64]int       // occurrences of each position hash, recorded from the first move on
+	history       []historyEntry       // applied moves, oldest first
+	undone        []Move               // undone moves, most recently undone last
+	observers     []GameObserver
+	rules         Rules
+	Turn          int
 }
 
+// Values returned by Winner besides the number of the winning player.
+// A player lost when the game is over and the winner is neither them nor Draw.
+const (
+	NoWinner int8 = 0  // the game is still running
+	Draw     int8 = -1 // the game ended without a winner
+)
+
+// RepetitionsForDraw is the number of occurrences of a same position that ends the game as a draw.
+const RepetitionsForDraw = 3
+
+// lineAxes are the directions used to enumerate each line of marbles once, from its first marble.
+var lineAxes = [3]Direction{Right, BottomRight, BottomLeft}
+
 var emptyGrid = buildEmptyGrid()
 var startingGrid = buildStartingGrid()
 
-func NewGame(grid [3][3]int8) *Game {
+// NewGame starts a game on grid with the default rules.
+func NewGame(grid *Grid) *Game {
+	return newGameWithRules(grid, DefaultRules())
+}
+
+// NewGameWithRules starts a game on grid following rules. The layout of the rules is ignored.
+func NewGameWithRules(grid *Grid, rules Rules) (*Game, error) {
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+
+	return newGameWithRules(grid, rules), nil
+}
+
+// newGameWithRules starts a game on grid following rules already validated.
+func newGameWithRules(grid *Grid, rules Rules) *Game {
 	game := &Game{
 		currentPlayer: 1,
+		rules:         rules,
 	}
 
-	game.grid = copyGrid(grid)
+	game.grid = *grid
+	game.cellsHash = game.hashCells()
 	return game
 }
 
@@ -47,96 +98,327 @@ func (g *Game) Show() string {
 	res := ""
 
 	res += fmt.Sprintf("Current player: %d\n", g.currentPlayer)
-	res += fmt.Sprintf("Grid:\n%s", showGrid(g.grid))
+	res += "Captures:"
+	for player := int8(1); player <= g.rules.Players; player++ {
+		res += fmt.Sprintf(" %d: %d", player, g.score[player])
+	}
+	res += "\n"
+	res += fmt.Sprintf("Grid:\n%s", g.Render(DefaultRenderOptions()))
 
 	return res
 }
 
 func (g *Game) IsOver() bool {
-	return g.Winner != 0
+	return g.winner != NoWinner
+}
+
+// Winner returns the player who won the game, Draw if it ended without a winner
+// and NoWinner while it is still running.
+func (g *Game) Winner() int8 {
+	return g.winner
 }
 
-func (g *Game) SetGrid(c Coord2D, v int8) {
-	g.grid[c.Y][c.X] = v
+// CurrentPlayer returns the player who plays the next move.
+func (g *Game) CurrentPlayer() int8 {
+	return g.currentPlayer
 }
 
-func (g *Game) GetGrid(c Coord2D) int8 {
-	return g.grid[c.Y][c.X]
+// Players returns the number of players of the game.
+func (g *Game) Players() int8 {
+	return g.rules.Players
+}
+
+func (g *Game) nextPlayer() int8 {
+	return g.currentPlayer%g.rules.Players + 1
+}
+
+// Rules returns the rules followed by the game.
+func (g *Game) Rules() Rules {
+	return g.rules
+}
+
+// Score returns the number of enemy marbles ejected by player.
+func (g *Game) Score(player int8) int8 {
+	return g.score[player]
+}
+
+func (g *Game) SetGrid(c Coord3D, v int8) {
+	g.putCell(c, v)
+}
+
+func (g *Game) GetGrid(c Coord3D) int8 {
+	return g.grid.get(c)
+}
+
+func (g *Game) Copy() *Game {
+	newGame := newGameWithRules(&g.grid, g.rules)
+	newGame.currentPlayer = g.currentPlayer
+	newGame.score = g.score
+	newGame.Turn = g.Turn
+	newGame.winner = g.winner
+
+	if g.positions != nil {
+		newGame.positions = make(map[uint64]int, len(g.positions))
+		for position, count := range g.positions {
+			newGame.positions[position] = count
+		}
+	}
+
+	newGame.history = append([]historyEntry(nil), g.history...)
+	newGame.undone = append([]Move(nil), g.undone...)
+
+	return newGame
 }
 
-func (g *Game) Put(at Coord2D) error {
-	err := g.checkCanPut(at)
+// Push moves the line of current player marbles starting at from one cell towards direction.
+// Up to MaxGroupSize marbles can move together and push a smaller line of enemy marbles (sumito).
+// A marble pushed out of the hexagon is ejected and counted in the score of its owner's opponent.
+func (g *Game) Push(from Coord3D, direction Direction) error {
+	mine, enemies, err := g.checkCanPush(from, direction)
 	if err != nil {
-		log.Println(fmt.Sprintf("Cannot put at %v: %s", at, err.Error()))
 		return err
 	}
 
-	g.grid[at.Y][at.X] = g.currentPlayer
+	g.startTurn(PushLine{From: from, Direction: direction})
 
-	g.currentPlayer = 3 - g.currentPlayer
-	g.Turn += 1
+	// shift the whole line, starting from its head
+	entry := &g.history[len(g.history)-1]
+	head := from
+	for i := 0; i < mine+enemies; i++ {
+		if i > 0 {
+			head = head.Add(direction)
+		}
+
+		if i < mine {
+			entry.moved = append(entry.moved, head)
+		} else {
+			entry.pushed = append(entry.pushed, head)
+		}
+	}
+
+	for c := head; ; c = c.Add(direction.Opposite()) {
+		next := c.Add(direction)
+		if IsValidCoord(next) {
+			g.setCell(next, g.grid.get(c))
+		} else {
+			g.eject(c, g.grid.get(c))
+		}
 
-	winner := g.checkWinner()
-	if winner != 0 {
-		g.Winner = winner + 1
-		//log.Println(fmt.Sprintf("Winner: %d", g.Winner))
+		if c == from {
+			break
+		}
 	}
+	g.setCell(from, 0)
+
+	g.endTurn()
+	g.notifyObservers()
 
 	return nil
 }
 
-func (g *Game) checkWinner() int8 {
-	// check if one line is full
-	for i := 0; i < 3; i++ {
-		if g.grid[i][0] != 0 && g.grid[i][0] == g.grid[i][1] && g.grid[i][1] == g.grid[i][2] {
-			return g.grid[i][0]
-		}
+// Broadside moves count aligned marbles, starting at from and going towards axis,
+// one cell sideways towards direction. Every destination must be an empty cell.
+func (g *Game) Broadside(from Coord3D, axis Direction, count int, direction Direction) error {
+	move := Broadside{From: from, Axis: axis, Count: count, Direction: direction}
+
+	err := g.checkCanBroadside(move)
+	if err != nil {
+		return err
+	}
+
+	g.startTurn(move)
+	g.history[len(g.history)-1].moved = move.Marbles()
+
+	for _, c := range move.Marbles() {
+		g.setCell(c.Add(direction), g.currentPlayer)
+		g.setCell(c, 0)
+	}
+
+	g.endTurn()
+	g.notifyObservers()
+
+	return nil
+}
+
+func (g *Game) checkCanBroadside(move Broadside) error {
+	if g.IsOver() {
+		return errors.New("game is over")
+	}
+
+	if move.Count < 2 || move.Count > g.rules.MaxGroupSize {
+		return errors.New(fmt.Sprintf("invalid broadside size (min 2, max %d, got %d)", g.rules.MaxGroupSize, move.Count))
+	}
+
+	if move.Direction == move.Axis || move.Direction == move.Axis.Opposite() {
+		return errors.New("broadside direction must not be along the line")
+	}
 
-		if g.grid[0][i] != 0 && g.grid[0][i] == g.grid[1][i] && g.grid[1][i] == g.grid[2][i] {
-			return g.grid[0][i]
+	for _, c := range move.Marbles() {
+		if !IsValidCoord(c) || g.grid.get(c) != g.currentPlayer {
+			return errors.New(fmt.Sprintf("no marble of player %d at %v", g.currentPlayer, c))
 		}
 
-		if g.grid[0][0] != 0 && g.grid[0][0] == g.grid[1][1] && g.grid[1][1] == g.grid[2][2] {
-			return g.grid[0][0]
+		to := c.Add(move.Direction)
+		if !IsValidCoord(to) {
+			return errors.New("cannot push its own marbles out of the hexagon")
 		}
 
-		if g.grid[0][2] != 0 && g.grid[0][2] == g.grid[1][1] && g.grid[1][1] == g.grid[2][0] {
-			return g.grid[0][2]
+		if g.grid.get(to) != 0 {
+			return errors.New(fmt.Sprintf("cannot move to %v: cell is not empty", to))
 		}
 	}
 
-	return 0
+	return nil
 }
 
-func (g *Game) checkCanPut(at Coord2D) error {
-	if !IsValidCoord(at) {
-		return errors.New(fmt.Sprintf("Invalid coord: %v", at))
+// eject counts a marble of owner pushed out of the hexagon from at, for the current player,
+// or for the next player if the current player pushed out their own marble.
+func (g *Game) eject(at Coord3D, owner int8) {
+	scorer := g.currentPlayer
+	if owner == g.currentPlayer {
+		scorer = g.nextPlayer()
+	}
+
+	g.score[scorer] += 1
+
+	entry := &g.history[len(g.history)-1]
+	entry.captures = append(entry.captures, Capture{At: at, Owner: owner, Scorer: scorer})
+
+	if g.score[scorer] >= g.rules.MarblesToWin {
+		g.winner = scorer
 	}
+}
 
-	if g.grid[at.Y][at.X] != 0 {
-		return errors.New(fmt.Sprintf("Cannot play at %v: cell is not empty", at))
+// startTurn opens the history entry of move, and records the position before the first move,
+// once the board has been set up.
+func (g *Game) startTurn(move Move) {
+	entry := historyEntry{
+		move:   move,
+		player: g.currentPlayer,
+		score:  g.score,
+		winner: g.winner,
 	}
 
-	return nil
+	if g.positions == nil {
+		g.positions = map[uint64]int{g.Hash(): 1}
+		entry.firstMove = true
+	}
+
+	g.history = append(g.history, entry)
+	g.undone = nil
 }
 
-func (g *Game) Copy() *Game {
-	newGame := NewGame(g.grid)
-	newGame.currentPlayer = g.currentPlayer
-	newGame.Turn = g.Turn
-	newGame.Winner = g.Winner
-	return newGame
+func (g *Game) endTurn() {
+	g.currentPlayer = g.nextPlayer()
+	g.Turn += 1
+
+	position := g.Hash()
+	g.positions[position] += 1
+	g.history[len(g.history)-1].position = position
+
+	if g.IsOver() {
+		return
+	}
+
+	if g.positions[position] >= RepetitionsForDraw || (g.rules.MaxTurns > 0 && g.Turn >= g.rules.MaxTurns) {
+		g.winner = Draw
+	}
+}
+
+// Repetitions returns how many times the current position occurred since the first move.
+func (g *Game) Repetitions() int {
+	return max(g.positions[g.Hash()], 1)
+}
+
+// checkCanPush validates a push and returns the number of own and enemy marbles in the pushed line.
+func (g *Game) checkCanPush(from Coord3D, direction Direction) (int, int, error) {
+	return g.checkCanPushFor(g.currentPlayer, from, direction)
+}
+
+// checkCanPushFor validates a push of player, whether or not they are the current player.
+func (g *Game) checkCanPushFor(player int8, from Coord3D, direction Direction) (int, int, error) {
+	if g.IsOver() {
+		return 0, 0, errors.New("game is over")
+	}
+
+	if !IsValidCoord(from) {
+		return 0, 0, errors.New(fmt.Sprintf("invalid coord: %v", from))
+	}
+
+	if g.grid.get(from) != player {
+		return 0, 0, errors.New(fmt.Sprintf("no marble of player %d at %v", player, from))
+	}
+
+	mine := 0
+	c := from
+	for IsValidCoord(c) && g.grid.get(c) == player {
+		mine++
+		c = c.Add(direction)
+	}
+
+	if mine > g.rules.MaxGroupSize {
+		return 0, 0, errors.New(fmt.Sprintf("too many marbles to push (max %d, got %d)", g.rules.MaxGroupSize, mine))
+	}
+
+	if !IsValidCoord(c) {
+		if g.rules.AllowSelfEjection {
+			return mine, 0, nil
+		}
+
+		return 0, 0, errors.New("cannot push its own marbles out of the hexagon")
+	}
+
+	enemies := 0
+	for IsValidCoord(c) && g.grid.get(c) != 0 && g.grid.get(c) != player {
+		enemies++
+		c = c.Add(direction)
+	}
+
+	if enemies > g.rules.MaxGroupSize-1 {
+		return 0, 0, errors.New(fmt.Sprintf("too many enemy marbles to push (max %d, got %d)", g.rules.MaxGroupSize-1, enemies))
+	}
+
+	if enemies >= mine {
+		return 0, 0, errors.New(fmt.Sprintf("not enough marbles to push enemy (got %d, need %d)", mine, enemies+1))
+	}
+
+	if enemies > 0 && IsValidCoord(c) && g.grid.get(c) == player {
+		return 0, 0, errors.New("my marbles are sandwiching enemy marbles")
+	}
+
+	return mine, enemies, nil
 }
 
+// GetValidMoves lists every move the current player can make.
+// Each move appears once, in its canonical description, in the order defined by CompareMoves.
 func (g *Game) GetValidMoves() []Move {
 	moves := make([]Move, 0)
 
-	for i := 0; i < 3; i++ {
-		for j := 0; j < 3; j++ {
-			at := Coord2D{X: int8(j), Y: int8(i)}
+	for _, from := range boardCoords {
+		if g.grid.get(from) != g.currentPlayer {
+			continue
+		}
+
+		for _, direction := range Directions {
+			if _, _, err := g.checkCanPush(from, direction); err == nil {
+				moves = append(moves, PushLine{From: from, Direction: direction})
+			}
+		}
+	}
+
+	for _, from := range boardCoords {
+		if g.grid.get(from) != g.currentPlayer {
+			continue
+		}
 
-			if g.grid[i][j] == 0 {
-				moves = append(moves, Move{At: at})
+		for _, axis := range lineAxes {
+			for count := 2; count <= g.rules.MaxGroupSize; count++ {
+				for _, direction := range Directions {
+					move := Broadside{From: from, Axis: axis, Count: count, Direction: direction}
+					if g.checkCanBroadside(move) == nil {
+						moves = append(moves, move)
+					}
+				}
 			}
 		}
 	}
@@ -144,18 +426,48 @@ func (g *Game) GetValidMoves() []Move {
 	return moves
 }
 
-func (g *Game) Move(move Move) error {
-	return g.Put(move.At)
-}
+// MovedMarbles lists the current player marbles that move would displace, in line order.
+func (g *Game) MovedMarbles(move Move) ([]Coord3D, error) {
+	switch m := move.(type) {
+	case PushLine:
+		mine, _, err := g.checkCanPush(m.From, m.Direction)
+		if err != nil {
+			return nil, err
+		}
 
-func copyGrid(grid [3][3]int8) [3][3]int8 {
-	var newGrid [3][3]int8
+		marbles := make([]Coord3D, 0, mine)
+		for i, c := 0, m.From; i < mine; i, c = i+1, c.Add(m.Direction) {
+			marbles = append(marbles, c)
+		}
 
-	for i := 0; i < 3; i++ {
-		for j := 0; j < 3; j++ {
-			newGrid[i][j] = grid[i][j]
+		return marbles, nil
+	case Broadside:
+		if err := g.checkCanBroadside(m); err != nil {
+			return nil, err
 		}
+
+		return m.Marbles(), nil
+	default:
+		return nil, errors.New(fmt.Sprintf("unsupported move: %v", move))
+	}
+}
+
+// Move plays move for the current player and describes what it changed.
+func (g *Game) Move(move Move) (MoveResult, error) {
+	var err error
+
+	switch m := move.(type) {
+	case PushLine:
+		err = g.Push(m.From, m.Direction)
+	case Broadside:
+		err = g.Broadside(m.From, m.Axis, m.Count, m.Direction)
+	default:
+		err = errors.New(fmt.Sprintf("unsupported move: %v", move))
+	}
+
+	if err != nil {
+		return MoveResult{}, err
 	}
 
-	return newGrid
+	return g.lastMoveResult(), nil
 }
@@ -2,6 +2,7 @@ package engine
 
 import (
 	"abalone-go/helpers"
+	"strings"
 	"testing"
 )
 
@@ -244,6 +245,10 @@ func TestGetValidMovesWithTwoMarbles(t *testing.T) {
 		PushLine{From: Coord3D{1, -1, 0}, Direction: BottomLeft},
 		PushLine{From: Coord3D{1, -1, 0}, Direction: Left},
 		PushLine{From: Coord3D{1, -1, 0}, Direction: TopLeft},
+		Broadside{From: Coord3D{0, 0, 0}, Axis: Right, Count: 2, Direction: TopRight},
+		Broadside{From: Coord3D{0, 0, 0}, Axis: Right, Count: 2, Direction: BottomRight},
+		Broadside{From: Coord3D{0, 0, 0}, Axis: Right, Count: 2, Direction: BottomLeft},
+		Broadside{From: Coord3D{0, 0, 0}, Axis: Right, Count: 2, Direction: TopLeft},
 	}
 
 	helpers.AssertEqual(expected, moves)
@@ -278,3 +283,416 @@ func TestCannotSuicide(t *testing.T) {
 
 	helpers.AssertEqual("cannot push its own marbles out of the hexagon", err.Error())
 }
+
+func TestBroadsideTwo(t *testing.T) {
+	game := NewGame(&emptyGrid)
+	game.SetGrid(Coord3D{0, 0, 0}, 1)
+	game.SetGrid(Coord3D{1, -1, 0}, 1)
+
+	err := game.Broadside(Coord3D{0, 0, 0}, Right, 2, TopLeft)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	expected := NewGame(&emptyGrid)
+	expected.SetGrid(Coord3D{0, 1, -1}, 1)
+	expected.SetGrid(Coord3D{1, 0, -1}, 1)
+
+	helpers.AssertEqual(showGrid(expected.grid), showGrid(game.grid))
+	helpers.AssertEqual(int8(2), game.currentPlayer)
+}
+
+func TestBroadsideBlocked(t *testing.T) {
+	game := NewGame(&emptyGrid)
+	game.SetGrid(Coord3D{0, 0, 0}, 1)
+	game.SetGrid(Coord3D{1, -1, 0}, 1)
+	game.SetGrid(Coord3D{1, 0, -1}, 2)
+
+	err := game.Broadside(Coord3D{0, 0, 0}, Right, 2, TopLeft)
+
+	helpers.AssertEqual("cannot move to (x: 1, y: 0, z: -1): cell is not empty", err.Error())
+}
+
+func TestBroadsideOutOfHexagon(t *testing.T) {
+	game := NewGame(&emptyGrid)
+	game.SetGrid(Coord3D{0, 4, -4}, 1)
+	game.SetGrid(Coord3D{1, 3, -4}, 1)
+
+	err := game.Broadside(Coord3D{0, 4, -4}, Right, 2, TopRight)
+
+	helpers.AssertEqual("cannot push its own marbles out of the hexagon", err.Error())
+}
+
+func TestWinAfterEjectingEnoughMarbles(t *testing.T) {
+	game := NewGame(&emptyGrid)
+	game.SetGrid(Coord3D{2, -2, 0}, 1)
+	game.SetGrid(Coord3D{3, -3, 0}, 1)
+	game.SetGrid(Coord3D{4, -4, 0}, 2)
+	game.score[1] = DefaultMarblesToWin - 1
+
+	err := game.Push(Coord3D{2, -2, 0}, Right)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(true, game.IsOver())
+	helpers.AssertEqual(int8(1), game.Winner())
+	helpers.AssertEqual(0, len(game.GetValidMoves()))
+}
+
+func TestBuiltinLayoutsHaveFourteenMarblesEach(t *testing.T) {
+	for _, name := range []string{StandardLayout, BelgianDaisyLayout, GermanDaisyLayout, SwissDaisyLayout, DutchDaisyLayout} {
+		game, err := NewGameFromLayout(name)
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+
+		counts := [3]int{}
+		for _, c := range boardCoords {
+			counts[game.GetGrid(c)]++
+		}
+
+		helpers.AssertEqual([3]int{33, 14, 14}, counts)
+	}
+}
+
+func TestLoadLayouts(t *testing.T) {
+	names, err := LoadLayouts(strings.NewReader(`
+# a single marble each
+layout duel
+    . . . . .
+   . . . . . .
+  . . . 2 . . .
+ . . . . . . . .
+. . . . . . . . .
+ . . . . . . . .
+  . . . 1 . . .
+   . . . . . .
+    . . . . .
+`))
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual([]string{"duel"}, names)
+
+	game, err := NewGameFromLayout("duel")
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(int8(1), game.GetGrid(Coord3D{-1, -1, 2}))
+	helpers.AssertEqual(int8(2), game.GetGrid(Coord3D{1, 1, -2}))
+}
+
+func TestMoveEquality(t *testing.T) {
+	push := PushLine{From: Coord3D{0, 0, 0}, Direction: Right}
+
+	helpers.AssertEqual(true, push.Equal(PushLine{From: Coord3D{0, 0, 0}, Direction: Right}))
+	helpers.AssertEqual(false, push.Equal(PushLine{From: Coord3D{0, 0, 0}, Direction: Left}))
+	helpers.AssertEqual(false, push.Equal(Broadside{From: Coord3D{0, 0, 0}, Axis: Right, Count: 2, Direction: TopRight}))
+	helpers.AssertEqual("E5E6", push.String())
+}
+
+func TestMovedMarbles(t *testing.T) {
+	game := NewGame(&emptyGrid)
+	game.SetGrid(Coord3D{0, 0, 0}, 1)
+	game.SetGrid(Coord3D{1, -1, 0}, 1)
+	game.SetGrid(Coord3D{2, -2, 0}, 2)
+
+	marbles, err := game.MovedMarbles(PushLine{From: Coord3D{0, 0, 0}, Direction: Right})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual([]Coord3D{{0, 0, 0}, {1, -1, 0}}, marbles)
+}
+
+func TestBroadsideCanonical(t *testing.T) {
+	fromStart := Broadside{From: Coord3D{0, 0, 0}, Axis: Right, Count: 3, Direction: TopLeft}
+	fromEnd := Broadside{From: Coord3D{2, -2, 0}, Axis: Left, Count: 3, Direction: TopLeft}
+
+	helpers.AssertEqual(Move(fromStart), fromEnd.Canonical())
+	helpers.AssertEqual(true, fromEnd.Equal(fromStart))
+}
+
+func TestGetValidMovesAreUniqueAndSorted(t *testing.T) {
+	game, err := NewGameFromLayout(BelgianDaisyLayout)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	moves := game.GetValidMoves()
+
+	for i := 1; i < len(moves); i++ {
+		if CompareMoves(moves[i-1], moves[i]) >= 0 {
+			t.Fatalf("Moves not strictly ordered: %v then %v", moves[i-1], moves[i])
+		}
+	}
+
+	for _, move := range moves {
+		helpers.AssertEqual(move, move.Canonical())
+	}
+}
+
+func TestDrawByRepetition(t *testing.T) {
+	game := NewGame(&emptyGrid)
+	game.SetGrid(Coord3D{0, 0, 0}, 1)
+	game.SetGrid(Coord3D{0, 4, -4}, 2)
+
+	for _, notation := range []string{"E5E6", "I5I6", "E6E5", "I6I5", "E5E6", "I5I6", "E6E5"} {
+		helpers.AssertEqual(false, game.IsOver())
+
+		if err := game.PlayNotation(notation); err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+	}
+
+	helpers.AssertEqual(false, game.IsOver())
+
+	if err := game.PlayNotation("I6I5"); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(3, game.Repetitions())
+	helpers.AssertEqual(Draw, game.Winner())
+}
+
+func TestDrawByMoveLimit(t *testing.T) {
+	rules := DefaultRules()
+	rules.MaxTurns = 2
+
+	game, err := NewGameWithRules(&emptyGrid, rules)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	game.SetGrid(Coord3D{0, 0, 0}, 1)
+	game.SetGrid(Coord3D{0, 4, -4}, 2)
+
+	if err := game.PlayNotation("E5E6"); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(false, game.IsOver())
+
+	if err := game.PlayNotation("I5I6"); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(Draw, game.Winner())
+}
+
+func TestUndoRedo(t *testing.T) {
+	game, err := NewGameFromLayout(BelgianDaisyLayout)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	positions := []string{game.String()}
+
+	for i := 0; i < 200 && !game.IsOver(); i++ {
+		if _, err := game.Move(helpers.RandIn(game.GetValidMoves())); err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+		positions = append(positions, game.String())
+	}
+
+	helpers.AssertEqual(len(positions)-1, len(game.Moves()))
+
+	for i := len(positions) - 2; i >= 0; i-- {
+		if err := game.Undo(); err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+		helpers.AssertEqual(positions[i], game.String())
+	}
+
+	helpers.AssertEqual(false, game.CanUndo())
+	helpers.AssertEqual(NoWinner, game.Winner())
+
+	for i := 1; i < len(positions); i++ {
+		if err := game.Redo(); err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+		helpers.AssertEqual(positions[i], game.String())
+	}
+
+	helpers.AssertEqual(false, game.CanRedo())
+}
+
+func TestUndoRestoresEjectedMarble(t *testing.T) {
+	game := NewGame(&emptyGrid)
+	game.SetGrid(Coord3D{2, -2, 0}, 1)
+	game.SetGrid(Coord3D{3, -3, 0}, 1)
+	game.SetGrid(Coord3D{4, -4, 0}, 2)
+	before := showGrid(game.grid)
+
+	if err := game.Push(Coord3D{2, -2, 0}, Right); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	if err := game.Undo(); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(before, showGrid(game.grid))
+	helpers.AssertEqual(int8(0), game.Score(1))
+	helpers.AssertEqual(int8(1), game.currentPlayer)
+}
+
+func TestSelfEjectionWhenAllowed(t *testing.T) {
+	rules := DefaultRules()
+	rules.AllowSelfEjection = true
+
+	game, err := NewGameWithRules(&emptyGrid, rules)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	game.SetGrid(Coord3D{4, -4, 0}, 1)
+
+	err = game.Push(Coord3D{4, -4, 0}, Right)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(showGrid(emptyGrid), showGrid(game.grid))
+	helpers.AssertEqual(int8(1), game.Score(2))
+}
+
+func TestMaxGroupSize(t *testing.T) {
+	rules := DefaultRules()
+	rules.MaxGroupSize = 2
+
+	game, err := NewGameWithRules(&emptyGrid, rules)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	game.SetGrid(Coord3D{-1, 1, 0}, 1)
+	game.SetGrid(Coord3D{0, 0, 0}, 1)
+	game.SetGrid(Coord3D{1, -1, 0}, 1)
+
+	err = game.Push(Coord3D{-1, 1, 0}, Right)
+
+	helpers.AssertEqual("too many marbles to push (max 2, got 3)", err.Error())
+}
+
+func TestNewGameWithInvalidRules(t *testing.T) {
+	_, err := NewGameWithRules(&startingGrid, Rules{MarblesToWin: 6, MaxGroupSize: 3})
+	helpers.AssertEqual("invalid rules: players must be between 2 and 6, got 0", err.Error())
+
+	rules := DefaultRules()
+	rules.MaxGroupSize = 0
+
+	_, err = NewGameWithRules(&startingGrid, rules)
+	helpers.AssertEqual("invalid rules: max group size must be between 1 and 9, got 0", err.Error())
+}
+
+func TestThreePlayers(t *testing.T) {
+	game, err := NewGameFromRules(ThreePlayerRules())
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	counts := [4]int{}
+	for _, c := range boardCoords {
+		counts[game.GetGrid(c)]++
+	}
+	helpers.AssertEqual([4]int{28, 11, 11, 11}, counts)
+
+	for _, player := range []int8{1, 2, 3, 1} {
+		helpers.AssertEqual(player, game.CurrentPlayer())
+
+		if _, err := game.Move(game.GetValidMoves()[0]); err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+	}
+}
+
+func TestThreePlayersPushMixedEnemies(t *testing.T) {
+	game, err := NewGameWithRules(&emptyGrid, ThreePlayerRules())
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	game.SetGrid(Coord3D{0, 0, 0}, 1)
+	game.SetGrid(Coord3D{1, -1, 0}, 1)
+	game.SetGrid(Coord3D{2, -2, 0}, 1)
+	game.SetGrid(Coord3D{3, -3, 0}, 2)
+	game.SetGrid(Coord3D{4, -4, 0}, 3)
+
+	err = game.Push(Coord3D{0, 0, 0}, Right)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(int8(2), game.GetGrid(Coord3D{4, -4, 0}))
+	helpers.AssertEqual(int8(1), game.Score(1))
+	helpers.AssertEqual(int8(2), game.CurrentPlayer())
+	helpers.AssertEqual("5/6/7/8/5wwwb/8/7/6/5 b 1 1-0-0", game.String())
+}
+
+func TestRenderHighlights(t *testing.T) {
+	game := NewGame(&emptyGrid)
+	game.SetGrid(Coord3D{2, -2, 0}, 1)
+	game.SetGrid(Coord3D{3, -3, 0}, 1)
+	game.SetGrid(Coord3D{4, -4, 0}, 2)
+	game.SetGrid(Coord3D{0, 4, -4}, 1)
+
+	if err := game.PlayNotation("I5I6"); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	expected := "" +
+		"    I *.*w . . .\n" +
+		"   H  . . . . . .\n" +
+		"  G  . . . . . . .\n" +
+		" F  . . . . . . . .\n" +
+		"E  . . . . . . w w!b\n" +
+		" D  . . . . . . . . 9\n" +
+		"  C  . . . . . . . 8\n" +
+		"   B  . . . . . . 7\n" +
+		"    A  . . . . . 6\n" +
+		"        1 2 3 4 5\n"
+
+	helpers.AssertEqual(expected, game.Render(RenderOptions{Mode: RenderASCII, HighlightLastMove: true, HighlightDanger: true}))
+}
+
+func TestMoveResultOfACapture(t *testing.T) {
+	game := NewGame(&emptyGrid)
+	game.SetGrid(Coord3D{1, -1, 0}, 1)
+	game.SetGrid(Coord3D{2, -2, 0}, 1)
+	game.SetGrid(Coord3D{3, -3, 0}, 1)
+	game.SetGrid(Coord3D{4, -4, 0}, 2)
+	game.score[1] = DefaultMarblesToWin - 1
+
+	result, err := game.Move(PushLine{From: Coord3D{1, -1, 0}, Direction: Right})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(int8(1), result.Player)
+	helpers.AssertEqual([]Coord3D{{1, -1, 0}, {2, -2, 0}, {3, -3, 0}}, result.Moved)
+	helpers.AssertEqual([]Coord3D{{4, -4, 0}}, result.Pushed)
+	helpers.AssertEqual(true, result.IsCapture())
+	helpers.AssertEqual(Capture{At: Coord3D{4, -4, 0}, Owner: 2, Scorer: 1}, *result.Ejected)
+	helpers.AssertEqual(int8(DefaultMarblesToWin), result.Score)
+	helpers.AssertEqual(true, result.EndedGame())
+	helpers.AssertEqual(int8(1), result.Winner)
+}
+
+func TestMoveResultOfABroadside(t *testing.T) {
+	game := NewGame(&emptyGrid)
+	game.SetGrid(Coord3D{0, 0, 0}, 1)
+	game.SetGrid(Coord3D{1, -1, 0}, 1)
+
+	result, err := game.Move(Broadside{From: Coord3D{0, 0, 0}, Axis: Right, Count: 2, Direction: TopRight})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual([]Coord3D{{0, 0, 0}, {1, -1, 0}}, result.Moved)
+	helpers.AssertEqual(0, len(result.Pushed))
+	helpers.AssertEqual(false, result.IsCapture())
+	helpers.AssertEqual(false, result.EndedGame())
+
+	_, err = game.Move(PushLine{From: Coord3D{0, 0, 0}, Direction: Right})
+	helpers.AssertEqual(true, err != nil)
+}
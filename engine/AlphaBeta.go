@@ -0,0 +1,429 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+)
+
+/**
+ * AlphaBetaPlayer searches the game tree with negamax and alpha-beta pruning,
+ * scoring the leaves with its Evaluator.
+ *
+ * The search is iterative deepening: it searches 1 move ahead, then 2, and so on up to Depth,
+ * starting each iteration with the best move of the previous one.
+ * When the context deadline passes or TimeBudget is spent, the search stops and plays the best move
+ * of the last completed depth, while a cancelled context aborts the search.
+ * The first depth is always completed, even past the deadline, unless the context is cancelled.
+ *
+ * Negamax assumes that each move is the best one for the player making it against the player to move next:
+ * with more than two players, it plays as if every opponent was the next one.
+ *
+ * Moves are tried winning moves first, then captures, then in GetValidMoves order,
+ * so that the best moves are usually searched first and prune the others.
+ *
+ * With a Table, positions with a hash (see HashedState) are looked up before being searched,
+ * and their best move is tried first. Repetition draws are not part of the hash:
+ * the table can miss that a stored position has become a draw.
+ */
+type AlphaBetaPlayer struct {
+	Depth      int           // maximum depth, 0 for no limit besides the time
+	TimeBudget time.Duration // time allowed for each move, 0 for no limit besides the context
+	Evaluator  Evaluator
+	Table      *TranspositionTable // optional, can be shared between players with the same evaluator
+	Verbose    bool                // log each completed depth
+	LastSearch SearchInfo          // report of the last search
+}
+
+// SearchInfo reports a search.
+type SearchInfo struct {
+	Move    Move          // best move of the last completed depth
+	Score   float64       // score of Move for the player to move
+	Depth   int           // last completed depth
+	Nodes   int64         // positions searched, including the unfinished depth
+	Elapsed time.Duration // duration of the search
+}
+
+func (i SearchInfo) String() string {
+	return fmt.Sprintf("move %v, score %.1f, depth %d, %d nodes in %v", i.Move, i.Score, i.Depth, i.Nodes, i.Elapsed)
+}
+
+/**
+ * UndoableState is a GameState able to take back its last move, so that search can explore
+ * the tree on a single copy of the game.
+ */
+type UndoableState interface {
+	GameState
+	Undo() error
+}
+
+var _ UndoableState = (*Game)(nil)
+var _ UndoableState = (*MNKGame)(nil)
+
+func NewAlphaBetaPlayer(depth int, evaluator Evaluator) *AlphaBetaPlayer {
+	return &AlphaBetaPlayer{Depth: depth, Evaluator: evaluator}
+}
+
+// maxSearchDepth bounds the depth of a search without Depth.
+const maxSearchDepth = 64
+
+// searchStats counts the work done by a search.
+type searchStats struct {
+	nodes        int64
+	depthLimited bool // some position was evaluated before the game was over
+}
+
+func (p *AlphaBetaPlayer) ChooseMove(ctx context.Context, game GameState) (Move, error) {
+	info, err := p.Search(ctx, game)
+	if err != nil {
+		return nil, err
+	}
+
+	return info.Move, nil
+}
+
+// Search runs the iterative deepening search of the best move of game, and records it as LastSearch.
+func (p *AlphaBetaPlayer) Search(ctx context.Context, game GameState) (SearchInfo, error) {
+	start := time.Now()
+
+	maxDepth := p.Depth
+	if maxDepth == 0 {
+		maxDepth = maxSearchDepth
+	}
+
+	if maxDepth < 1 {
+		return SearchInfo{}, errors.New(fmt.Sprintf("invalid search depth: %d", p.Depth))
+	}
+
+	state, ok := game.Clone().(UndoableState)
+	if !ok {
+		return SearchInfo{}, errors.New(fmt.Sprintf("cannot search %T: moves cannot be undone", game))
+	}
+
+	moves, err := p.orderMoves(state)
+	if err != nil {
+		return SearchInfo{}, err
+	}
+
+	if len(moves) == 0 {
+		return SearchInfo{}, errors.New("no valid moves")
+	}
+
+	// deadlines only stop the depths after the first one
+	firstCtx, cancelFirst := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelFirst()
+
+	stopFirst := context.AfterFunc(ctx, func() {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			cancelFirst()
+		}
+	})
+	defer stopFirst()
+
+	budgetCtx := ctx
+	if p.TimeBudget > 0 {
+		var cancel context.CancelFunc
+		budgetCtx, cancel = context.WithTimeout(ctx, p.TimeBudget)
+		defer cancel()
+	}
+
+	if p.Table != nil {
+		p.Table.NewSearch()
+	}
+
+	info := SearchInfo{}
+	stats := &searchStats{}
+
+	for depth := 1; depth <= maxDepth; depth++ {
+		depthCtx := budgetCtx
+		if depth == 1 {
+			depthCtx = firstCtx
+		}
+
+		stats.depthLimited = false
+
+		move, score, err := p.searchRoot(depthCtx, state, depth, moves, stats)
+		if err != nil {
+			// out of time, the last completed depth is played, unless the search is aborted
+			if depth == 1 || budgetCtx.Err() == nil || errors.Is(ctx.Err(), context.Canceled) {
+				return SearchInfo{}, err
+			}
+			break
+		}
+
+		info.Move, info.Score, info.Depth = move, score, depth
+
+		if p.Verbose && p.Table != nil {
+			log.Println(fmt.Sprintf("Depth %d: best move %v, score %.1f, %d nodes, table: %v", depth, move, score, stats.nodes, p.Table.Stats()))
+		} else if p.Verbose {
+			log.Println(fmt.Sprintf("Depth %d: best move %v, score %.1f, %d nodes", depth, move, score, stats.nodes))
+		}
+
+		// the whole tree was searched, or the game is decided
+		if !stats.depthLimited || math.Abs(score) >= WinScore-maxSearchDepth {
+			break
+		}
+
+		moves = moveFirst(moves, move)
+	}
+
+	info.Nodes = stats.nodes
+	info.Elapsed = time.Since(start)
+	p.LastSearch = info
+
+	return info, nil
+}
+
+// moveFirst returns moves with move in first position, the others keeping their order.
+// Moves are unchanged if move is not one of them.
+func moveFirst(moves []Move, move Move) []Move {
+	found := false
+	for _, m := range moves {
+		found = found || m == move
+	}
+
+	if !found {
+		return moves
+	}
+
+	res := make([]Move, 0, len(moves))
+	res = append(res, move)
+
+	for _, m := range moves {
+		if m != move {
+			res = append(res, m)
+		}
+	}
+
+	return res
+}
+
+// searchRoot returns the best of moves in state searched depth moves ahead, and its score.
+func (p *AlphaBetaPlayer) searchRoot(ctx context.Context, state UndoableState, depth int, moves []Move, stats *searchStats) (Move, float64, error) {
+
+	var bestMove Move
+	bestScore := math.Inf(-1)
+	alpha := math.Inf(-1)
+	beta := math.Inf(1)
+
+	for _, move := range moves {
+		if _, err := state.Move(move); err != nil {
+			return nil, 0, err
+		}
+
+		score, err := p.negamax(ctx, state, depth-1, 1, -beta, -alpha, stats)
+		if undoErr := state.Undo(); undoErr != nil {
+			return nil, 0, undoErr
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+
+		score = -score
+		if bestMove == nil || score > bestScore {
+			bestMove, bestScore = move, score
+		}
+
+		alpha = max(alpha, score)
+	}
+
+	return bestMove, bestScore, nil
+}
+
+// negamax returns the score of state for the player to move, searched depth moves ahead.
+// ply is the number of moves played since the root.
+func (p *AlphaBetaPlayer) negamax(ctx context.Context, state UndoableState, depth int, ply int, alpha float64, beta float64, stats *searchStats) (float64, error) {
+	stats.nodes++
+
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	if state.IsOver() {
+		return terminalScore(state, ply), nil
+	}
+
+	if depth == 0 {
+		stats.depthLimited = true
+		return p.Evaluator.Evaluate(state, state.CurrentPlayer())
+	}
+
+	hashed, useTable := state.(HashedState)
+	useTable = useTable && p.Table != nil
+
+	var tableMove Move
+	alphaBefore := alpha
+
+	if useTable {
+		if entry, ok := p.Table.Probe(hashed.Hash()); ok {
+			tableMove = entry.BestMove
+
+			if entry.Depth >= depth {
+				score := scoreFromTable(entry.Score, ply)
+
+				// the stored search may have been cut by its depth, this one must deepen further
+				if !entry.Complete {
+					stats.depthLimited = true
+				}
+
+				switch entry.Bound {
+				case BoundExact:
+					return score, nil
+				case BoundLower:
+					alpha = max(alpha, score)
+				case BoundUpper:
+					beta = min(beta, score)
+				}
+
+				if alpha >= beta {
+					return score, nil
+				}
+			}
+		}
+	}
+
+	var moves []Move
+	var err error
+	if depth > 1 {
+		moves, err = p.orderMoves(state)
+		if err != nil {
+			return 0, err
+		}
+	} else {
+		moves = state.GetValidMoves()
+	}
+
+	if len(moves) == 0 {
+		return p.Evaluator.Evaluate(state, state.CurrentPlayer())
+	}
+
+	if tableMove != nil {
+		moves = moveFirst(moves, tableMove)
+	}
+
+	best := math.Inf(-1)
+	var bestMove Move
+
+	// tell whether the subtree of this position, and not only the whole search, is depth limited
+	limitedBefore := stats.depthLimited
+	stats.depthLimited = false
+
+	for _, move := range moves {
+		if _, err := state.Move(move); err != nil {
+			return 0, err
+		}
+
+		score, err := p.negamax(ctx, state, depth-1, ply+1, -beta, -alpha, stats)
+		if undoErr := state.Undo(); undoErr != nil {
+			return 0, undoErr
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		score = -score
+		if bestMove == nil || score > best {
+			best, bestMove = score, move
+		}
+		alpha = max(alpha, score)
+
+		if alpha >= beta {
+			break
+		}
+	}
+
+	complete := !stats.depthLimited
+	stats.depthLimited = stats.depthLimited || limitedBefore
+
+	if useTable {
+		bound := BoundExact
+		if best <= alphaBefore {
+			bound = BoundUpper
+		} else if best >= beta {
+			bound = BoundLower
+		}
+
+		p.Table.Store(TTEntry{
+			Hash:     hashed.Hash(),
+			Depth:    depth,
+			Bound:    bound,
+			Score:    scoreToTable(best, ply),
+			BestMove: bestMove,
+			Complete: complete,
+		})
+	}
+
+	return best, nil
+}
+
+// scoreToTable makes the scores of won games relative to the stored position instead of the root,
+// as the position can be reached at another ply.
+func scoreToTable(score float64, ply int) float64 {
+	if score >= WinScore-maxSearchDepth {
+		return score + float64(ply)
+	}
+
+	if score <= -(WinScore - maxSearchDepth) {
+		return score - float64(ply)
+	}
+
+	return score
+}
+
+// scoreFromTable is the inverse of scoreToTable.
+func scoreFromTable(score float64, ply int) float64 {
+	if score >= WinScore-maxSearchDepth {
+		return score - float64(ply)
+	}
+
+	if score <= -(WinScore - maxSearchDepth) {
+		return score + float64(ply)
+	}
+
+	return score
+}
+
+// terminalScore scores a game over for the player to move, ply moves after the root.
+func terminalScore(state GameState, ply int) float64 {
+	switch state.Winner() {
+	case Draw:
+		return 0
+	case state.CurrentPlayer():
+		return WinScore - float64(ply)
+	default:
+		return -(WinScore - float64(ply))
+	}
+}
+
+// orderMoves lists the valid moves of state, winning moves first, then captures.
+func (p *AlphaBetaPlayer) orderMoves(state UndoableState) ([]Move, error) {
+	moves := state.GetValidMoves()
+	ranks := make(map[Move]int, len(moves))
+
+	for _, move := range moves {
+		result, err := state.Move(move)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := state.Undo(); err != nil {
+			return nil, err
+		}
+
+		if result.Winner == result.Player {
+			ranks[move] = 2
+		} else if result.IsCapture() && result.Ejected.Scorer == result.Player {
+			ranks[move] = 1
+		}
+	}
+
+	sort.SliceStable(moves, func(i, j int) bool {
+		return ranks[moves[i]] > ranks[moves[j]]
+	})
+
+	return moves, nil
+}
@@ -0,0 +1,147 @@
+package engine
+
+import (
+	"abalone-go/helpers"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestAlphaBetaNeverLosesAtTicTacToe(t *testing.T) {
+	for gameId := 0; gameId < 10; gameId++ {
+		game := NewTicTacToe()
+		players := []Player{&RandomPlayer{}, NewAlphaBetaPlayer(9, DefaultHeuristicEvaluator())}
+
+		if _, err := PlayGame(context.Background(), game, players); err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+
+		helpers.AssertEqual(false, game.Winner() == 1)
+	}
+}
+
+func TestAlphaBetaBlocksTheOpponentLine(t *testing.T) {
+	game := NewTicTacToe()
+
+	// 1 1 .
+	// . 2 .
+	// . . .
+	playPlacements(t, game, Coord2D{0, 0}, Coord2D{1, 1}, Coord2D{1, 0})
+
+	move, err := NewAlphaBetaPlayer(2, DefaultHeuristicEvaluator()).ChooseMove(context.Background(), game)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(Placement{At: Coord2D{2, 0}}, move)
+	helpers.AssertEqual(3, game.Turn)
+}
+
+func TestAlphaBetaPlaysTheWinningPush(t *testing.T) {
+	game := NewGame(&emptyGrid)
+	game.SetGrid(Coord3D{0, 0, 0}, 1)
+	game.SetGrid(Coord3D{2, -2, 0}, 1)
+	game.SetGrid(Coord3D{3, -3, 0}, 1)
+	game.SetGrid(Coord3D{4, -4, 0}, 2)
+	game.SetGrid(Coord3D{-4, 4, 0}, 2)
+	game.score[1] = DefaultMarblesToWin - 1
+
+	move, err := NewAlphaBetaPlayer(3, DefaultHeuristicEvaluator()).ChooseMove(context.Background(), game)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(PushLine{From: Coord3D{2, -2, 0}, Direction: Right}, move)
+	helpers.AssertEqual(0, len(game.Moves()))
+}
+
+func TestHeuristicPrefersCapturesAndTheCenter(t *testing.T) {
+	evaluator := DefaultHeuristicEvaluator()
+
+	game := NewGame(&emptyGrid)
+	game.SetGrid(Coord3D{0, 0, 0}, 1)
+	game.SetGrid(Coord3D{4, -4, 0}, 2)
+
+	center, _ := evaluator.Evaluate(game, 1)
+	edge, _ := evaluator.Evaluate(game, 2)
+	helpers.AssertEqual(true, center > 0)
+	helpers.AssertEqual(-center, edge)
+
+	game.score[2] = 1
+	captured, _ := evaluator.Evaluate(game, 1)
+	helpers.AssertEqual(true, captured < 0)
+}
+
+func TestHeuristicRejectsUnsupportedGames(t *testing.T) {
+	_, err := DefaultHeuristicEvaluator().Evaluate(struct{ *Game }{NewGame(&emptyGrid)}, 1)
+	helpers.AssertEqual("unsupported game struct { *engine.Game }", err.Error())
+}
+
+func TestIterativeDeepeningSolvesTicTacToe(t *testing.T) {
+	player := &AlphaBetaPlayer{Evaluator: DefaultHeuristicEvaluator()}
+
+	info, err := player.Search(context.Background(), NewTicTacToe())
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	// the whole tree is searched, and the game is a draw
+	helpers.AssertEqual(9, info.Depth)
+	helpers.AssertEqual(0.0, info.Score)
+	helpers.AssertEqual(info, player.LastSearch)
+}
+
+func TestIterativeDeepeningStopsAtTheTimeBudget(t *testing.T) {
+	game, err := NewGameFromLayout(BelgianDaisyLayout)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	player := &AlphaBetaPlayer{TimeBudget: 50 * time.Millisecond, Evaluator: DefaultHeuristicEvaluator()}
+
+	info, err := player.Search(context.Background(), game)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(true, info.Depth >= 1)
+	helpers.AssertEqual(true, info.Nodes > 0)
+	helpers.AssertEqual(true, info.Elapsed < time.Second)
+
+	valid := false
+	for _, move := range game.GetValidMoves() {
+		valid = valid || move.Equal(info.Move)
+	}
+	helpers.AssertEqual(true, valid)
+}
+
+func TestIterativeDeepeningStopsAtTheContextDeadline(t *testing.T) {
+	game, err := NewGameFromLayout(BelgianDaisyLayout)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	info, err := (&AlphaBetaPlayer{Evaluator: DefaultHeuristicEvaluator()}).Search(ctx, game)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(true, info.Depth >= 1)
+
+	valid := false
+	for _, move := range game.GetValidMoves() {
+		valid = valid || move.Equal(info.Move)
+	}
+	helpers.AssertEqual(true, valid)
+}
+
+func TestCancelledSearchFails(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := NewAlphaBetaPlayer(3, DefaultHeuristicEvaluator()).ChooseMove(ctx, NewGame(&startingGrid))
+	helpers.AssertEqual(context.Canceled, err)
+}
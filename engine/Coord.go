@@ -20,6 +20,10 @@ type Coord2D struct {
 	Y int8
 }
 
+func (c Coord2D) String() string {
+	return fmt.Sprintf("(x: %d, y: %d)", c.X, c.Y)
+}
+
 func (c Coord2D) To3D() Coord3D {
 	return Coord3D{c.X, -c.X - c.Y, c.Y}
 }
@@ -45,11 +49,12 @@ func (c Coord3D) Add(direction Direction) Coord3D {
 	default:
 		panic("Invalid direction")
 	}
-
-	return Coord3D{}
 }
 
-func IsValidCoord(c Coord2D) bool {
-	return helpers.Between(c.X, 0, 2) &&
-		helpers.Between(c.Y, 0, 2)
+// IsValidCoord tells whether c is a cell of the hexagonal board.
+func IsValidCoord(c Coord3D) bool {
+	return c.X+c.Y+c.Z == 0 &&
+		helpers.Between(c.X, -BoardRadius, BoardRadius) &&
+		helpers.Between(c.Y, -BoardRadius, BoardRadius) &&
+		helpers.Between(c.Z, -BoardRadius, BoardRadius)
 }
@@ -31,3 +31,7 @@ func (d Direction) String() string {
 		panic("Invalid direction")
 	}
 }
+
+func (d Direction) Opposite() Direction {
+	return Directions[(int(d)+3)%len(Directions)]
+}
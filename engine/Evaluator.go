@@ -0,0 +1,141 @@
+package engine
+
+import (
+	"abalone-go/helpers"
+	"errors"
+	"fmt"
+)
+
+/**
+ * Evaluator scores positions for search players.
+ *
+ * Evaluate returns how good state is for player: the higher the better.
+ * Scores must stay well below WinScore, which search players use for won games.
+ */
+type Evaluator interface {
+	Evaluate(state GameState, player int8) (float64, error)
+}
+
+// WinScore is the score of a won game. Search players subtract the number of moves
+// needed to win, to prefer the fastest wins and the slowest losses.
+const WinScore = 1000000000.0
+
+/**
+ * HeuristicEvaluator scores positions with handcrafted features.
+ *
+ * On abalone, each feature is the value of the player minus the average value of their opponents:
+ * - captures: ejected enemy marbles
+ * - center: closeness of the marbles to the center of the hexagon, marbles on the edge are in danger
+ * - cohesion: pairs of neighbor marbles of a same player, groups are harder to push
+ *
+ * On an m,n,k-game, it counts the lines of K cells still open to each player,
+ * weighting each line by the square of the stones it already holds.
+ */
+type HeuristicEvaluator struct {
+	CaptureWeight  float64
+	CenterWeight   float64
+	CohesionWeight float64
+}
+
+func DefaultHeuristicEvaluator() *HeuristicEvaluator {
+	return &HeuristicEvaluator{
+		CaptureWeight:  1000,
+		CenterWeight:   10,
+		CohesionWeight: 3,
+	}
+}
+
+func (e *HeuristicEvaluator) Evaluate(state GameState, player int8) (float64, error) {
+	switch g := state.(type) {
+	case *Game:
+		return e.evaluateAbalone(g, player), nil
+	case *MNKGame:
+		return e.evaluateMNK(g, player), nil
+	default:
+		return 0, errors.New(fmt.Sprintf("unsupported game %T", state))
+	}
+}
+
+func (e *HeuristicEvaluator) evaluateAbalone(g *Game, player int8) float64 {
+	var values [MaxPlayers + 1]float64
+
+	for p := int8(1); p <= g.rules.Players; p++ {
+		values[p] = e.CaptureWeight * float64(g.score[p])
+	}
+
+	for _, c := range boardCoords {
+		owner := g.grid.get(c)
+		if owner == 0 {
+			continue
+		}
+
+		distance := max(helpers.Abs(c.X), helpers.Abs(c.Y), helpers.Abs(c.Z))
+		values[owner] += e.CenterWeight * float64(BoardRadius-distance)
+
+		// count each pair once, from its first marble
+		for _, axis := range lineAxes {
+			if next := c.Add(axis); IsValidCoord(next) && g.grid.get(next) == owner {
+				values[owner] += e.CohesionWeight
+			}
+		}
+	}
+
+	return relativeValue(values[:g.rules.Players+1], player)
+}
+
+func (e *HeuristicEvaluator) evaluateMNK(g *MNKGame, player int8) float64 {
+	var values [3]float64
+
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			for _, axis := range mnkAxes {
+				endX := x + int(axis[0])*(g.K-1)
+				endY := y + int(axis[1])*(g.K-1)
+				if endX < 0 || endX >= g.Width || endY < 0 || endY >= g.Height {
+					continue
+				}
+
+				var stones [3]int
+				for i := 0; i < g.K; i++ {
+					stones[g.grid[(y+int(axis[1])*i)*g.Width+x+int(axis[0])*i]]++
+				}
+
+				// a line holding stones of both players is dead
+				for p := 1; p <= 2; p++ {
+					if stones[3-p] == 0 {
+						values[p] += float64(stones[p] * stones[p])
+					}
+				}
+			}
+		}
+	}
+
+	return relativeValue(values[:], player)
+}
+
+// relativeValue returns the value of player minus the average value of the other players, indexed from 1.
+func relativeValue(values []float64, player int8) float64 {
+	others := 0.0
+	for p := 1; p < len(values); p++ {
+		if int8(p) != player {
+			others += values[p]
+		}
+	}
+
+	return values[player] - others/float64(len(values)-2)
+}
+
+// Evaluate scores state with the network. The network scores states from the point of view
+// of the player who just moved, which is the opponent of the player to move in a two players game.
+func (p *NeatPlayer) Evaluate(state GameState, player int8) (float64, error) {
+	score, err := p.evaluateState(state)
+	if err != nil {
+		return 0, err
+	}
+
+	if state.CurrentPlayer() == player {
+		return -score, nil
+	}
+
+	return score, nil
+}
@@ -0,0 +1,50 @@
+package engine
+
+/**
+ * GameState is a game as seen by players, evaluators and the NEAT training harness.
+ * Both the abalone Game and the m,n,k-game sandbox implement it.
+ *
+ * Clone returns an independent copy: Game.Copy and MNKGame.Copy keep their concrete return type.
+ */
+type GameState interface {
+	GetValidMoves() []Move
+	Move(move Move) (MoveResult, error)
+	Clone() GameState
+	IsOver() bool
+	Winner() int8
+	CurrentPlayer() int8
+
+	// Features encodes the state as network inputs. Their count only depends on the game and its rules.
+	Features() []float64
+}
+
+var _ GameState = (*Game)(nil)
+var _ GameState = (*MNKGame)(nil)
+
+func (g *Game) Clone() GameState {
+	return g.Copy()
+}
+
+// Features encodes, for each cell and each player, whether the cell holds a marble of the player,
+// followed by the captures of each player relative to MarblesToWin.
+func (g *Game) Features() []float64 {
+	players := int(g.rules.Players)
+	features := make([]float64, 0, len(boardCoords)*players+players)
+
+	for _, c := range boardCoords {
+		v := g.grid.get(c)
+		for player := int8(1); player <= g.rules.Players; player++ {
+			if v == player {
+				features = append(features, 1.0)
+			} else {
+				features = append(features, 0.0)
+			}
+		}
+	}
+
+	for player := int8(1); player <= g.rules.Players; player++ {
+		features = append(features, float64(g.score[player])/float64(g.rules.MarblesToWin))
+	}
+
+	return features
+}
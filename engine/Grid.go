@@ -1,14 +1,43 @@
 package engine
 
-import "fmt"
+import (
+	"abalone-go/helpers"
+	"fmt"
+	"strings"
+)
 
-func showGrid(grid [3][3]int8) string {
+// BoardRadius is the number of cells between the center and an edge of the hexagon.
+const BoardRadius = 4
+
+const boardSize = 2*BoardRadius + 1
+
+/**
+ * Grid is the hexagonal abalone board.
+ *
+ * Cells are stored in axial layout: the first index is the row (Z),
+ * the second one is the column (X), both shifted by BoardRadius.
+ * Slots that fall outside the hexagon are never used.
+ */
+type Grid [boardSize][boardSize]int8
+
+// boardCoords lists every cell of the hexagon, row by row from the top, left to right.
+var boardCoords = buildBoardCoords()
+
+func (grid *Grid) get(c Coord3D) int8 {
+	return grid[c.Z+BoardRadius][c.X+BoardRadius]
+}
+
+func (grid *Grid) set(c Coord3D, v int8) {
+	grid[c.Z+BoardRadius][c.X+BoardRadius] = v
+}
+
+func showGrid(grid Grid) string {
 	res := ""
 
-	for i := 0; i < 3; i++ {
-		res += "  "
-		for j := 0; j < 3; j++ {
-			res += fmt.Sprintf("%d ", grid[i][j])
+	for z := int8(-BoardRadius); z <= BoardRadius; z++ {
+		res += "  " + strings.Repeat(" ", int(helpers.Abs(z)))
+		for _, c := range rowCoords(z) {
+			res += fmt.Sprintf("%d ", grid.get(c))
 		}
 		res += "\n"
 	}
@@ -16,10 +45,39 @@ func showGrid(grid [3][3]int8) string {
 	return res
 }
 
-func buildEmptyGrid() [3][3]int8 {
-	return [3][3]int8{}
+// rowCoords returns the cells of row z, from left to right.
+func rowCoords(z int8) []Coord3D {
+	coords := make([]Coord3D, 0, boardSize)
+
+	for x := int8(-BoardRadius); x <= BoardRadius; x++ {
+		c := Coord2D{X: x, Y: z}.To3D()
+		if IsValidCoord(c) {
+			coords = append(coords, c)
+		}
+	}
+
+	return coords
+}
+
+func buildBoardCoords() []Coord3D {
+	coords := make([]Coord3D, 0)
+
+	for z := int8(-BoardRadius); z <= BoardRadius; z++ {
+		coords = append(coords, rowCoords(z)...)
+	}
+
+	return coords
+}
+
+func buildEmptyGrid() Grid {
+	return Grid{}
 }
 
-func buildStartingGrid() [3][3]int8 {
-	return copyGrid(emptyGrid)
+func buildStartingGrid() Grid {
+	grid, err := ParseLayout(builtinLayouts[StandardLayout])
+	if err != nil {
+		panic(err)
+	}
+
+	return grid
 }
@@ -0,0 +1,101 @@
+package engine
+
+import "errors"
+
+// historyEntry holds what is needed to undo an applied move.
+type historyEntry struct {
+	move      Move
+	player    int8                 // player who played the move
+	cells     []cellChange         // cells modified by the move, in modification order
+	captures  []Capture            // marbles ejected by the move
+	moved     []Coord3D            // own marbles displaced by the move, before the move
+	pushed    []Coord3D            // enemy marbles displaced by the move, before the move
+	score     [MaxPlayers + 1]int8 // scores before the move
+	winner    int8                 // winner before the move
+	position  uint64               // position hash recorded after the move
+	firstMove bool                 // the position before the move was recorded by this move
+}
+
+// cellChange is the previous value of a modified cell.
+type cellChange struct {
+	at       Coord3D
+	previous int8
+}
+
+// setCell changes a cell during a move, remembering its previous value.
+func (g *Game) setCell(c Coord3D, v int8) {
+	entry := &g.history[len(g.history)-1]
+	entry.cells = append(entry.cells, cellChange{at: c, previous: g.grid.get(c)})
+
+	g.putCell(c, v)
+}
+
+// Moves returns the applied moves, oldest first.
+func (g *Game) Moves() []Move {
+	moves := make([]Move, 0, len(g.history))
+	for _, entry := range g.history {
+		moves = append(moves, entry.move)
+	}
+
+	return moves
+}
+
+// CanUndo tells whether there is a move to undo.
+func (g *Game) CanUndo() bool {
+	return len(g.history) > 0
+}
+
+// CanRedo tells whether there is an undone move to replay.
+func (g *Game) CanRedo() bool {
+	return len(g.undone) > 0
+}
+
+// Undo takes back the last applied move, restoring moved, pushed and ejected marbles.
+func (g *Game) Undo() error {
+	if !g.CanUndo() {
+		return errors.New("no move to undo")
+	}
+
+	entry := g.history[len(g.history)-1]
+	g.history = g.history[:len(g.history)-1]
+
+	for i := len(entry.cells) - 1; i >= 0; i-- {
+		g.putCell(entry.cells[i].at, entry.cells[i].previous)
+	}
+
+	g.positions[entry.position] -= 1
+	if g.positions[entry.position] == 0 {
+		delete(g.positions, entry.position)
+	}
+
+	if entry.firstMove {
+		g.positions = nil
+	}
+
+	g.currentPlayer = entry.player
+	g.score = entry.score
+	g.winner = entry.winner
+	g.Turn -= 1
+
+	g.undone = append(g.undone, entry.move)
+
+	return nil
+}
+
+// Redo replays the last undone move. Applying any other move clears the undone moves.
+func (g *Game) Redo() error {
+	if !g.CanRedo() {
+		return errors.New("no move to redo")
+	}
+
+	move := g.undone[len(g.undone)-1]
+	undone := g.undone[:len(g.undone)-1]
+
+	if _, err := g.Move(move); err != nil {
+		return err
+	}
+
+	g.undone = undone
+
+	return nil
+}
@@ -0,0 +1,285 @@
+package engine
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+/**
+ * Layouts are named starting positions.
+ *
+ * A layout is written as one line per row of the hexagon, from the top row to the bottom one,
+ * with one whitespace separated token per cell:
+ * - "." or "0": empty
+ * - "1" to "6": marble of that player
+ *
+ * A layout file contains any number of layouts, each one introduced by a "layout <name>" line.
+ * Empty lines and lines starting with "#" are ignored.
+ */
+
+const (
+	StandardLayout     = "standard"
+	BelgianDaisyLayout = "belgian_daisy"
+	GermanDaisyLayout  = "german_daisy"
+	SwissDaisyLayout   = "swiss_daisy"
+	DutchDaisyLayout   = "dutch_daisy"
+	ThreePlayerLayout  = "three_player"
+)
+
+var builtinLayouts = map[string]string{
+	StandardLayout: `
+		2 2 2 2 2
+		2 2 2 2 2 2
+		. . 2 2 2 . .
+		. . . . . . . .
+		. . . . . . . . .
+		. . . . . . . .
+		. . 1 1 1 . .
+		1 1 1 1 1 1
+		1 1 1 1 1`,
+	BelgianDaisyLayout: `
+		1 1 . 2 2
+		1 1 1 2 2 2
+		. 1 1 . 2 2 .
+		. . . . . . . .
+		. . . . . . . . .
+		. . . . . . . .
+		. 2 2 . 1 1 .
+		2 2 2 1 1 1
+		2 2 . 1 1`,
+	GermanDaisyLayout: `
+		. . . . .
+		1 1 . . 2 2
+		1 1 1 . 2 2 2
+		. 1 1 . . 2 2 .
+		. . . . . . . . .
+		. 2 2 . . 1 1 .
+		2 2 2 . 1 1 1
+		2 2 . . 1 1
+		. . . . .`,
+	SwissDaisyLayout: `
+		. . . . .
+		1 1 . . 2 2
+		1 2 1 . 2 1 2
+		. 1 1 . . 2 2 .
+		. . . . . . . . .
+		. 2 2 . . 1 1 .
+		2 1 2 . 1 2 1
+		2 2 . . 1 1
+		. . . . .`,
+	DutchDaisyLayout: `
+		1 1 . 2 2
+		1 2 1 2 1 2
+		. 1 1 . 2 2 .
+		. . . . . . . .
+		. . . . . . . . .
+		. . . . . . . .
+		. 2 2 . 1 1 .
+		2 1 2 1 2 1
+		2 2 . 1 1`,
+	ThreePlayerLayout: `
+		3 3 . 2 2
+		3 3 . . 2 2
+		3 3 . . . 2 2
+		3 3 . . . . 2 2
+		3 3 . . . . . 2 2
+		3 . . . . . . 2
+		. . . . . . .
+		1 1 1 1 1 1
+		1 1 1 1 1`,
+}
+
+var layoutsMutex sync.RWMutex
+var layouts = buildBuiltinLayouts()
+
+func buildBuiltinLayouts() map[string]Grid {
+	res := make(map[string]Grid)
+
+	for name, rows := range builtinLayouts {
+		grid, err := ParseLayout(rows)
+		if err != nil {
+			panic(fmt.Sprintf("Invalid builtin layout %s: %s", name, err.Error()))
+		}
+
+		res[name] = grid
+	}
+
+	return res
+}
+
+// ParseLayout reads a single layout, one line per row of the hexagon.
+func ParseLayout(rows string) (Grid, error) {
+	lines := make([]string, 0)
+
+	for _, line := range strings.Split(rows, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return parseLayoutRows(lines)
+}
+
+func parseLayoutRows(lines []string) (Grid, error) {
+	grid := buildEmptyGrid()
+
+	if len(lines) != boardSize {
+		return grid, errors.New(fmt.Sprintf("invalid layout: expected %d rows, got %d", boardSize, len(lines)))
+	}
+
+	for i, line := range lines {
+		z := int8(i - BoardRadius)
+		coords := rowCoords(z)
+		cells := strings.Fields(line)
+
+		if len(cells) != len(coords) {
+			return grid, errors.New(fmt.Sprintf("invalid layout: expected %d cells in row %d, got %d", len(coords), i+1, len(cells)))
+		}
+
+		for j, cell := range cells {
+			if cell == "." {
+				continue
+			}
+
+			v, err := strconv.ParseInt(cell, 10, 8)
+			if err != nil || v < 0 || v > MaxPlayers {
+				return grid, errors.New(fmt.Sprintf("invalid layout: unknown cell %q in row %d", cell, i+1))
+			}
+
+			grid.set(coords[j], int8(v))
+		}
+	}
+
+	return grid, nil
+}
+
+// RegisterLayout adds a named layout, replacing any layout with the same name.
+func RegisterLayout(name string, grid Grid) {
+	layoutsMutex.Lock()
+	defer layoutsMutex.Unlock()
+
+	layouts[name] = grid
+}
+
+// GetLayout returns the layout registered under name.
+func GetLayout(name string) (Grid, error) {
+	layoutsMutex.RLock()
+	defer layoutsMutex.RUnlock()
+
+	grid, ok := layouts[name]
+	if !ok {
+		return Grid{}, errors.New(fmt.Sprintf("unknown layout: %s", name))
+	}
+
+	return grid, nil
+}
+
+// LayoutNames returns the names of all registered layouts, sorted.
+func LayoutNames() []string {
+	layoutsMutex.RLock()
+	defer layoutsMutex.RUnlock()
+
+	names := make([]string, 0, len(layouts))
+	for name := range layouts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// LoadLayouts registers every layout of a layout file and returns their names.
+func LoadLayouts(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+
+	parsed := make(map[string]Grid)
+	names := make([]string, 0)
+
+	name := ""
+	rows := make([]string, 0)
+
+	flush := func() error {
+		if name == "" {
+			return nil
+		}
+
+		grid, err := parseLayoutRows(rows)
+		if err != nil {
+			return errors.New(fmt.Sprintf("layout %s: %s", name, err.Error()))
+		}
+
+		parsed[name] = grid
+		names = append(names, name)
+		return nil
+	}
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if fields := strings.Fields(line); fields[0] == "layout" {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+
+			if len(fields) != 2 {
+				return nil, errors.New(fmt.Sprintf("invalid layout header: %q", line))
+			}
+
+			name = fields[1]
+			rows = rows[:0]
+			continue
+		}
+
+		if name == "" {
+			return nil, errors.New(fmt.Sprintf("row outside of any layout: %q", line))
+		}
+
+		rows = append(rows, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		RegisterLayout(name, parsed[name])
+	}
+
+	return names, nil
+}
+
+// LoadLayoutsFromFile registers every layout of the layout file at path.
+func LoadLayoutsFromFile(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	return LoadLayouts(f)
+}
+
+// NewGameFromLayout starts a game on the layout registered under name, with the default rules.
+func NewGameFromLayout(name string) (*Game, error) {
+	rules := DefaultRules()
+	rules.Layout = name
+
+	return NewGameFromRules(rules)
+}
@@ -0,0 +1,257 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+)
+
+/**
+ * MCTSPlayer chooses its moves with a Monte Carlo tree search, selecting nodes with UCT.
+ *
+ * Each iteration walks down the tree to a node with untried moves, adds one of them to the tree,
+ * then plays the game to its end with the Rollout player and credits the result to every node of the path:
+ * 1 for a win of the player who moved into the node, 0.5 for a draw, 0 for a loss.
+ * The most visited move of the root is played.
+ *
+ * The search stops after Iterations iterations or when TimeBudget is spent, whichever comes first.
+ * Rollout can be any player: a RandomPlayer for uniform random games, a NeatPlayer for net-guided ones.
+ *
+ * With ReuseTree, the subtree of the played move is kept for the next move, and found again
+ * from the hash of the new position (see HashedState) once the opponents have moved.
+ */
+type MCTSPlayer struct {
+	Iterations      int           // iterations for each move, 0 for no limit besides the time
+	TimeBudget      time.Duration // time allowed for each move, 0 for no limit besides the iterations
+	Exploration     float64       // UCT exploration constant, sqrt(2) in theory
+	Rollout         Player        // plays the simulated games
+	MaxRolloutMoves int           // simulated games stopped after these moves count as draws, 0 for no limit
+	ReuseTree       bool
+	Verbose         bool       // log each search
+	LastSearch      SearchInfo // report of the last search: Nodes counts the iterations, Depth the deepest node
+
+	root *mctsNode // subtree kept by ReuseTree
+}
+
+type mctsNode struct {
+	move     Move // move leading to the node
+	player   int8 // player who played move
+	hash     uint64
+	parent   *mctsNode
+	children []*mctsNode
+	untried  []Move
+	visits   int
+	reward   float64 // total reward of player
+}
+
+// DefaultIterations is the number of iterations of an MCTSPlayer without any limit.
+const DefaultIterations = 1000
+
+func NewMCTSPlayer(iterations int, rollout Player) *MCTSPlayer {
+	return &MCTSPlayer{
+		Iterations:  iterations,
+		Exploration: math.Sqrt2,
+		Rollout:     rollout,
+		ReuseTree:   true,
+	}
+}
+
+func (p *MCTSPlayer) ChooseMove(ctx context.Context, game GameState) (Move, error) {
+	info, err := p.Search(ctx, game)
+	if err != nil {
+		return nil, err
+	}
+
+	return info.Move, nil
+}
+
+// Search runs the tree search of the best move of game, and records it as LastSearch.
+func (p *MCTSPlayer) Search(ctx context.Context, game GameState) (SearchInfo, error) {
+	start := time.Now()
+
+	if game.IsOver() || len(game.GetValidMoves()) == 0 {
+		return SearchInfo{}, errors.New("no valid moves")
+	}
+
+	iterations := p.Iterations
+	if iterations == 0 && p.TimeBudget == 0 {
+		iterations = DefaultIterations
+	}
+
+	rollout := p.Rollout
+	if rollout == nil {
+		rollout = &RandomPlayer{}
+	}
+
+	root := p.findRoot(game)
+
+	maxDepth := 0
+	count := 0
+
+	for ; iterations == 0 || count < iterations; count++ {
+		if err := ctx.Err(); err != nil {
+			return SearchInfo{}, err
+		}
+
+		// at least one iteration, whatever the time
+		if p.TimeBudget > 0 && count > 0 && time.Since(start) >= p.TimeBudget {
+			break
+		}
+
+		depth, err := p.iterate(ctx, root, game, rollout)
+		if err != nil {
+			return SearchInfo{}, err
+		}
+
+		maxDepth = max(maxDepth, depth)
+	}
+
+	best := root.children[0]
+	for _, child := range root.children[1:] {
+		if child.visits > best.visits {
+			best = child
+		}
+	}
+
+	info := SearchInfo{
+		Move:    best.move,
+		Score:   best.reward / float64(best.visits),
+		Depth:   maxDepth,
+		Nodes:   int64(count),
+		Elapsed: time.Since(start),
+	}
+
+	if p.Verbose {
+		log.Println(fmt.Sprintf("MCTS: %v, %d root visits", info, root.visits))
+	}
+
+	p.root = nil
+	if p.ReuseTree {
+		best.parent = nil
+		p.root = best
+	}
+
+	p.LastSearch = info
+
+	return info, nil
+}
+
+// findRoot returns the kept subtree node of the position of game, or a new root.
+func (p *MCTSPlayer) findRoot(game GameState) *mctsNode {
+	hashed, ok := game.(HashedState)
+
+	if ok && p.root != nil {
+		// the opponents played at most a round since the kept node
+		level := []*mctsNode{p.root}
+		for depth := 0; depth <= MaxPlayers && len(level) > 0; depth++ {
+			next := make([]*mctsNode, 0)
+
+			for _, node := range level {
+				if node.hash == hashed.Hash() {
+					node.parent = nil
+					return node
+				}
+				next = append(next, node.children...)
+			}
+
+			level = next
+		}
+	}
+
+	root := &mctsNode{untried: game.GetValidMoves()}
+	if ok {
+		root.hash = hashed.Hash()
+	}
+
+	return root
+}
+
+// iterate runs one iteration from root, a node of the position of game, and returns the depth of the new node.
+func (p *MCTSPlayer) iterate(ctx context.Context, root *mctsNode, game GameState, rollout Player) (int, error) {
+	state := game.Clone()
+	node := root
+	depth := 0
+
+	// selection
+	for len(node.untried) == 0 && len(node.children) > 0 {
+		node = p.selectChild(node)
+		depth++
+
+		if _, err := state.Move(node.move); err != nil {
+			return 0, err
+		}
+	}
+
+	// expansion
+	if len(node.untried) > 0 {
+		move := node.untried[len(node.untried)-1]
+		node.untried = node.untried[:len(node.untried)-1]
+
+		player := state.CurrentPlayer()
+		if _, err := state.Move(move); err != nil {
+			return 0, err
+		}
+
+		child := &mctsNode{move: move, player: player, parent: node}
+		if !state.IsOver() {
+			child.untried = state.GetValidMoves()
+		}
+		if hashed, ok := state.(HashedState); ok {
+			child.hash = hashed.Hash()
+		}
+
+		node.children = append(node.children, child)
+		node = child
+		depth++
+	}
+
+	// simulation
+	for moves := 0; !state.IsOver() && (p.MaxRolloutMoves == 0 || moves < p.MaxRolloutMoves); moves++ {
+		if len(state.GetValidMoves()) == 0 {
+			break
+		}
+
+		move, err := rollout.ChooseMove(ctx, state)
+		if err != nil {
+			return 0, err
+		}
+
+		if _, err := state.Move(move); err != nil {
+			return 0, err
+		}
+	}
+
+	// backpropagation
+	for n := node; n != nil; n = n.parent {
+		n.visits++
+
+		switch state.Winner() {
+		case n.player:
+			n.reward += 1
+		case Draw, NoWinner:
+			n.reward += 0.5
+		}
+	}
+
+	return depth, nil
+}
+
+// selectChild returns the child of node with the best upper confidence bound.
+func (p *MCTSPlayer) selectChild(node *mctsNode) *mctsNode {
+	var best *mctsNode
+	bestValue := math.Inf(-1)
+
+	logVisits := math.Log(float64(node.visits))
+
+	for _, child := range node.children {
+		value := child.reward/float64(child.visits) + p.Exploration*math.Sqrt(logVisits/float64(child.visits))
+		if value > bestValue {
+			best, bestValue = child, value
+		}
+	}
+
+	return best
+}
@@ -0,0 +1,87 @@
+package engine
+
+import (
+	"abalone-go/helpers"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMCTSPlaysTheWinningMoveAtTicTacToe(t *testing.T) {
+	game := NewTicTacToe()
+
+	// 1 1 .
+	// 2 2 .
+	// . . .
+	playPlacements(t, game, Coord2D{0, 0}, Coord2D{0, 1}, Coord2D{1, 0}, Coord2D{1, 1})
+
+	move, err := NewMCTSPlayer(2000, &RandomPlayer{}).ChooseMove(context.Background(), game)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(Placement{At: Coord2D{2, 0}}, move)
+}
+
+func TestMCTSReusesTheTreeOfThePlayedMove(t *testing.T) {
+	rules := DefaultRules()
+	rules.MaxTurns = 20
+
+	game, err := NewGameWithRules(&emptyGrid, rules)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	game.SetGrid(Coord3D{0, 0, 0}, 1)
+	game.SetGrid(Coord3D{1, -1, 0}, 1)
+	game.SetGrid(Coord3D{0, 4, -4}, 2)
+
+	player := NewMCTSPlayer(500, &RandomPlayer{})
+
+	move, err := player.ChooseMove(context.Background(), game)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	if _, err := game.Move(move); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	kept := player.root
+	helpers.AssertEqual(game.Hash(), kept.hash)
+
+	if _, err := game.Move(helpers.RandIn(game.GetValidMoves())); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	// the new position is a child of the kept node, with its statistics
+	found := player.findRoot(game)
+	helpers.AssertEqual(game.Hash(), found.hash)
+	helpers.AssertEqual(true, found.visits > 0)
+	helpers.AssertEqual(true, found.parent == nil)
+}
+
+func TestMCTSStopsAtTheTimeBudget(t *testing.T) {
+	game, err := NewGameFromLayout(BelgianDaisyLayout)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	player := NewMCTSPlayer(0, &RandomPlayer{})
+	player.TimeBudget = 50 * time.Millisecond
+	player.MaxRolloutMoves = 20
+
+	info, err := player.Search(context.Background(), game)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(true, info.Nodes > 0)
+	helpers.AssertEqual(true, info.Elapsed < time.Second)
+	helpers.AssertEqual(info, player.LastSearch)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = player.Search(ctx, game)
+	helpers.AssertEqual(context.Canceled, err)
+}
@@ -0,0 +1,276 @@
+package engine
+
+import (
+	"abalone-go/helpers"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/**
+ * MNKGame is an m,n,k-game: two players put stones in turn on a Width x Height board,
+ * the first one to align K stones horizontally, vertically or diagonally wins.
+ * Tic-tac-toe is the 3,3,3-game and gomoku the 15,15,5-game.
+ *
+ * It is the fast sandbox used to validate the NEAT training pipeline before abalone.
+ *
+ * The grid is a Width x Height rectangle of integers, stored row by row:
+ * 0: empty
+ * 1: player 1
+ * 2: player 2
+ */
+type MNKGame struct {
+	Width         int
+	Height        int
+	K             int
+	grid          []int8 // 0: empty, 1: player 1, 2: player 2
+	currentPlayer int8   // 1 or 2
+	Turn          int
+	winner        int8      // see Winner
+	history       []Coord2D // placed stones, oldest first
+}
+
+// mnkAxes are the four line directions checked around a stone, as (dx, dy).
+var mnkAxes = [4][2]int8{{1, 0}, {0, 1}, {1, 1}, {1, -1}}
+
+// maxMNKSize is the largest board side, so that cells fit in a Coord2D.
+const maxMNKSize = 127
+
+func NewMNKGame(width int, height int, k int) (*MNKGame, error) {
+	if width < 1 || height < 1 || width > maxMNKSize || height > maxMNKSize {
+		return nil, errors.New(fmt.Sprintf("invalid board size: %dx%d", width, height))
+	}
+
+	if k < 1 || (k > width && k > height) {
+		return nil, errors.New(fmt.Sprintf("invalid line length %d for a %dx%d board", k, width, height))
+	}
+
+	game := &MNKGame{
+		Width:         width,
+		Height:        height,
+		K:             k,
+		grid:          make([]int8, width*height),
+		currentPlayer: 1,
+	}
+
+	return game, nil
+}
+
+func NewTicTacToe() *MNKGame {
+	game, _ := NewMNKGame(3, 3, 3)
+	return game
+}
+
+func NewGomoku() *MNKGame {
+	game, _ := NewMNKGame(15, 15, 5)
+	return game
+}
+
+func (g *MNKGame) Show() string {
+	res := ""
+
+	res += fmt.Sprintf("Current player: %d\n", g.currentPlayer)
+	res += fmt.Sprintf("Grid:\n%s", g.showGrid())
+
+	return res
+}
+
+func (g *MNKGame) showGrid() string {
+	res := ""
+
+	for y := 0; y < g.Height; y++ {
+		res += "  "
+		for x := 0; x < g.Width; x++ {
+			res += fmt.Sprintf("%d ", g.grid[y*g.Width+x])
+		}
+		res += "\n"
+	}
+
+	return res
+}
+
+func (g *MNKGame) IsOver() bool {
+	return g.winner != NoWinner
+}
+
+// Winner returns the player who aligned K stones, Draw if the board is full
+// without any line and NoWinner while the game is still running.
+func (g *MNKGame) Winner() int8 {
+	return g.winner
+}
+
+func (g *MNKGame) SetGrid(c Coord2D, v int8) {
+	g.grid[int(c.Y)*g.Width+int(c.X)] = v
+}
+
+func (g *MNKGame) GetGrid(c Coord2D) int8 {
+	return g.grid[int(c.Y)*g.Width+int(c.X)]
+}
+
+func (g *MNKGame) Put(at Coord2D) error {
+	err := g.checkCanPut(at)
+	if err != nil {
+		return err
+	}
+
+	g.SetGrid(at, g.currentPlayer)
+
+	if g.isWinningStone(at) {
+		g.winner = g.currentPlayer
+	} else if g.Turn+1 == g.Width*g.Height {
+		g.winner = Draw
+	}
+
+	g.currentPlayer = 3 - g.currentPlayer
+	g.Turn += 1
+	g.history = append(g.history, at)
+
+	return nil
+}
+
+// Undo takes back the last placed stone.
+func (g *MNKGame) Undo() error {
+	if len(g.history) == 0 {
+		return errors.New("no move to undo")
+	}
+
+	at := g.history[len(g.history)-1]
+	g.history = g.history[:len(g.history)-1]
+
+	g.SetGrid(at, 0)
+	g.currentPlayer = 3 - g.currentPlayer
+	g.Turn -= 1
+	g.winner = NoWinner
+
+	return nil
+}
+
+// isWinningStone tells whether the stone at at is part of a line of at least K stones.
+// Only the lines through the last stone can have changed, so there is no need to scan the board.
+func (g *MNKGame) isWinningStone(at Coord2D) bool {
+	player := g.GetGrid(at)
+
+	for _, axis := range mnkAxes {
+		count := 1 + g.countStones(at, axis[0], axis[1], player) + g.countStones(at, -axis[0], -axis[1], player)
+		if count >= g.K {
+			return true
+		}
+	}
+
+	return false
+}
+
+// countStones counts the consecutive stones of player from at (excluded) towards (dx, dy).
+func (g *MNKGame) countStones(at Coord2D, dx int8, dy int8, player int8) int {
+	count := 0
+
+	for c := (Coord2D{at.X + dx, at.Y + dy}); g.isValidCoord(c) && g.GetGrid(c) == player; c = (Coord2D{c.X + dx, c.Y + dy}) {
+		count++
+	}
+
+	return count
+}
+
+func (g *MNKGame) checkCanPut(at Coord2D) error {
+	if g.IsOver() {
+		return errors.New("game is over")
+	}
+
+	if !g.isValidCoord(at) {
+		return errors.New(fmt.Sprintf("Invalid coord: %v", at))
+	}
+
+	if g.GetGrid(at) != 0 {
+		return errors.New(fmt.Sprintf("Cannot play at %v: cell is not empty", at))
+	}
+
+	return nil
+}
+
+func (g *MNKGame) isValidCoord(c Coord2D) bool {
+	return helpers.Between(c.X, 0, int8(g.Width-1)) &&
+		helpers.Between(c.Y, 0, int8(g.Height-1))
+}
+
+func (g *MNKGame) Copy() *MNKGame {
+	newGame := *g
+	newGame.grid = append([]int8(nil), g.grid...)
+	newGame.history = append([]Coord2D(nil), g.history...)
+	return &newGame
+}
+
+func (g *MNKGame) Clone() GameState {
+	return g.Copy()
+}
+
+func (g *MNKGame) CurrentPlayer() int8 {
+	return g.currentPlayer
+}
+
+func (g *MNKGame) GetValidMoves() []Move {
+	moves := make([]Move, 0)
+
+	if g.IsOver() {
+		return moves
+	}
+
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			if g.grid[y*g.Width+x] == 0 {
+				moves = append(moves, Placement{At: Coord2D{X: int8(x), Y: int8(y)}})
+			}
+		}
+	}
+
+	return moves
+}
+
+func (g *MNKGame) Move(move Move) (MoveResult, error) {
+	placement, ok := move.(Placement)
+	if !ok {
+		return MoveResult{}, errors.New(fmt.Sprintf("unsupported move: %v", move))
+	}
+
+	player := g.currentPlayer
+
+	if err := g.Put(placement.At); err != nil {
+		return MoveResult{}, err
+	}
+
+	return MoveResult{Move: move, Player: player, Winner: g.winner}, nil
+}
+
+// ParseMove reads a placement written as its column and row, like "1,2".
+func (g *MNKGame) ParseMove(notation string) (Move, error) {
+	var x, y int8
+	if _, err := fmt.Sscanf(strings.TrimSpace(notation), "%d,%d", &x, &y); err != nil {
+		return nil, errors.New(fmt.Sprintf("invalid move %q: expected x,y", notation))
+	}
+
+	return Placement{At: Coord2D{X: x, Y: y}}, nil
+}
+
+// Features encodes, for each cell and each player, whether the cell holds a stone of the player.
+func (g *MNKGame) Features() []float64 {
+	features := make([]float64, 0, len(g.grid)*2)
+
+	for _, cellOwner := range g.grid {
+		player1 := 0.0
+		player2 := 0.0
+
+		if cellOwner == 1 {
+			player1 = 1.0
+		} else if cellOwner == 2 {
+			player2 = 1.0
+		}
+
+		features = append(features, player1, player2)
+	}
+
+	return features
+}
+
+// String describes the game as its Width,Height,K parameters.
+func (g *MNKGame) String() string {
+	return fmt.Sprintf("%d,%d,%d", g.Width, g.Height, g.K)
+}
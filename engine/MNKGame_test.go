@@ -0,0 +1,106 @@
+package engine
+
+import (
+	"abalone-go/helpers"
+	"testing"
+)
+
+func playPlacements(t *testing.T, game *MNKGame, coords ...Coord2D) {
+	for _, c := range coords {
+		err := game.Put(c)
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+	}
+}
+
+func TestTicTacToeDiagonalWin(t *testing.T) {
+	game := NewTicTacToe()
+
+	playPlacements(t, game,
+		Coord2D{0, 0}, Coord2D{1, 0},
+		Coord2D{1, 1}, Coord2D{2, 0},
+		Coord2D{2, 2})
+
+	helpers.AssertEqual(true, game.IsOver())
+	helpers.AssertEqual(int8(1), game.Winner())
+	helpers.AssertEqual(0, len(game.GetValidMoves()))
+}
+
+func TestTicTacToeDraw(t *testing.T) {
+	game := NewTicTacToe()
+
+	// 1 2 1
+	// 1 2 2
+	// 2 1 1
+	playPlacements(t, game,
+		Coord2D{0, 0}, Coord2D{1, 0},
+		Coord2D{2, 0}, Coord2D{1, 1},
+		Coord2D{0, 1}, Coord2D{0, 2},
+		Coord2D{1, 2}, Coord2D{2, 1},
+		Coord2D{2, 2})
+
+	helpers.AssertEqual(Draw, game.Winner())
+}
+
+func TestGomokuWinFromTheMiddleOfTheLine(t *testing.T) {
+	game := NewGomoku()
+
+	// player 1 fills the gap of an anti-diagonal line, player 2 plays far away
+	playPlacements(t, game,
+		Coord2D{5, 9}, Coord2D{0, 0},
+		Coord2D{6, 8}, Coord2D{1, 0},
+		Coord2D{8, 6}, Coord2D{2, 0},
+		Coord2D{9, 5}, Coord2D{3, 0})
+
+	helpers.AssertEqual(false, game.IsOver())
+
+	playPlacements(t, game, Coord2D{7, 7})
+
+	helpers.AssertEqual(int8(1), game.Winner())
+}
+
+func TestMNKGameFourInARowIsNotEnough(t *testing.T) {
+	game := NewGomoku()
+
+	playPlacements(t, game,
+		Coord2D{0, 14}, Coord2D{14, 0},
+		Coord2D{1, 14}, Coord2D{14, 1},
+		Coord2D{2, 14}, Coord2D{14, 2},
+		Coord2D{3, 14})
+
+	helpers.AssertEqual(false, game.IsOver())
+	helpers.AssertEqual(15*15-7, len(game.GetValidMoves()))
+}
+
+func TestMNKGameRejectsInvalidPlacements(t *testing.T) {
+	game, err := NewMNKGame(4, 3, 3)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	playPlacements(t, game, Coord2D{3, 2})
+
+	helpers.AssertEqual(true, game.Put(Coord2D{3, 2}) != nil)
+	helpers.AssertEqual(true, game.Put(Coord2D{4, 0}) != nil)
+	helpers.AssertEqual(true, game.Put(Coord2D{0, 3}) != nil)
+
+	_, err = NewMNKGame(3, 3, 4)
+	helpers.AssertEqual(true, err != nil)
+}
+
+func TestMNKGameParsesTheListedMoves(t *testing.T) {
+	game, err := NewMNKGame(4, 3, 3)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	for _, move := range game.GetValidMoves() {
+		parsed, err := game.ParseMove(move.String())
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+
+		helpers.AssertEqual(move, parsed)
+	}
+}
@@ -1,11 +1,193 @@
 package engine
 
-import "fmt"
+import (
+	"cmp"
+	"fmt"
+)
 
-type Move struct {
+/**
+ * Move is a move of any game of this package.
+ *
+ * It is one of:
+ * - Placement: a piece put on an empty cell of the tic-tac-toe grid
+ * - PushLine: an abalone line of marbles moving along its own axis, possibly pushing enemy marbles
+ * - Broadside: an abalone line of marbles moving sideways into empty cells
+ *
+ * Moves are small comparable values, games dispatch on their concrete type.
+ *
+ * A same move can have several descriptions (a broadside can start from either end of its line).
+ * Canonical returns the single description used by GetValidMoves, and CompareMoves
+ * defines the order in which GetValidMoves lists them:
+ * - by kind: placements, then inline pushes, then broadsides
+ * - then by first marble (or cell), row by row from the top, left to right
+ * - then by axis (in lineAxes order), by marble count and by direction (in Directions order)
+ */
+type Move interface {
+	fmt.Stringer
+
+	// Equal tells whether both moves describe the same action.
+	Equal(other Move) bool
+
+	// Canonical returns the canonical description of the move.
+	Canonical() Move
+
+	isMove()
+}
+
+// Placement puts a piece on an empty cell of the tic-tac-toe grid.
+type Placement struct {
 	At Coord2D
 }
 
-func (m Move) String() string {
-	return fmt.Sprintf("Move(%v)", m.At)
+func (m Placement) isMove() {}
+
+func (m Placement) Equal(other Move) bool {
+	o, ok := other.(Placement)
+	return ok && o == m
+}
+
+func (m Placement) Canonical() Move {
+	return m
+}
+
+// String writes the placement as its column and row, like "1,2", as read by MNKGame.ParseMove.
+func (m Placement) String() string {
+	return fmt.Sprintf("%d,%d", m.At.X, m.At.Y)
+}
+
+// PushLine moves the line of marbles starting at From one cell towards Direction.
+// The line is made of From and every marble of the same player following it towards Direction.
+type PushLine struct {
+	From      Coord3D
+	Direction Direction
+}
+
+func (m PushLine) isMove() {}
+
+func (m PushLine) Equal(other Move) bool {
+	o, ok := other.(PushLine)
+	return ok && o == m
+}
+
+func (m PushLine) Canonical() Move {
+	return m
+}
+
+func (m PushLine) String() string {
+	return m.Notation()
+}
+
+// Broadside moves Count aligned marbles, starting at From and going towards Axis,
+// one cell sideways towards Direction.
+type Broadside struct {
+	From      Coord3D
+	Axis      Direction
+	Count     int
+	Direction Direction
+}
+
+func (m Broadside) isMove() {}
+
+func (m Broadside) Equal(other Move) bool {
+	o, ok := other.(Broadside)
+	return ok && o.Canonical() == m.Canonical()
+}
+
+// Canonical describes the broadside from the end of its line that makes Axis one of lineAxes.
+func (m Broadside) Canonical() Move {
+	for _, axis := range lineAxes {
+		if m.Axis == axis {
+			return m
+		}
+	}
+
+	last := m.From
+	for i := 0; i < m.Count-1; i++ {
+		last = last.Add(m.Axis)
+	}
+
+	return Broadside{From: last, Axis: m.Axis.Opposite(), Count: m.Count, Direction: m.Direction}
+}
+
+// Marbles lists the marbles moved by the broadside, from From along Axis.
+func (m Broadside) Marbles() []Coord3D {
+	marbles := make([]Coord3D, 0, m.Count)
+
+	c := m.From
+	for i := 0; i < m.Count; i++ {
+		marbles = append(marbles, c)
+		c = c.Add(m.Axis)
+	}
+
+	return marbles
+}
+
+func (m Broadside) String() string {
+	return m.Notation()
+}
+
+// CompareMoves orders canonical moves the way GetValidMoves lists them.
+// It returns a negative number when a comes first, a positive one when b comes first and 0 when they are equal.
+func CompareMoves(a Move, b Move) int {
+	if c := cmp.Compare(moveKindRank(a), moveKindRank(b)); c != 0 {
+		return c
+	}
+
+	switch m := a.(type) {
+	case Placement:
+		o := b.(Placement)
+		return firstNonZero(cmp.Compare(m.At.Y, o.At.Y), cmp.Compare(m.At.X, o.At.X))
+	case PushLine:
+		o := b.(PushLine)
+		return firstNonZero(compareCoords(m.From, o.From), cmp.Compare(m.Direction, o.Direction))
+	case Broadside:
+		o := b.(Broadside)
+		return firstNonZero(
+			compareCoords(m.From, o.From),
+			cmp.Compare(lineAxisRank(m.Axis), lineAxisRank(o.Axis)),
+			cmp.Compare(m.Count, o.Count),
+			cmp.Compare(m.Direction, o.Direction),
+		)
+	default:
+		panic(fmt.Sprintf("Invalid move type %T", a))
+	}
+}
+
+func moveKindRank(m Move) int {
+	switch m.(type) {
+	case Placement:
+		return 0
+	case PushLine:
+		return 1
+	case Broadside:
+		return 2
+	default:
+		panic(fmt.Sprintf("Invalid move type %T", m))
+	}
+}
+
+func lineAxisRank(axis Direction) int {
+	for i, a := range lineAxes {
+		if a == axis {
+			return i
+		}
+	}
+
+	return len(lineAxes)
+}
+
+// compareCoords orders cells row by row from the top, left to right.
+func compareCoords(a Coord3D, b Coord3D) int {
+	return firstNonZero(cmp.Compare(a.Z, b.Z), cmp.Compare(a.X, b.X))
+}
+
+// firstNonZero returns the first non-zero comparison result.
+func firstNonZero(comparisons ...int) int {
+	for _, c := range comparisons {
+		if c != 0 {
+			return c
+		}
+	}
+
+	return 0
 }
@@ -0,0 +1,48 @@
+package engine
+
+/**
+ * MoveResult describes what a move changed, for UIs to animate it and for search to order moves.
+ *
+ * Marbles are listed by their cell before the move.
+ * For a placement, only Move, Player and Winner are set.
+ */
+type MoveResult struct {
+	Move    Move
+	Player  int8      // player who played the move
+	Moved   []Coord3D // own marbles displaced, in line order
+	Pushed  []Coord3D // enemy marbles displaced, in line order, including the ejected one
+	Ejected *Capture  // marble pushed out of the hexagon, if any
+	Score   int8      // captures of the scorer after the move, when a marble was ejected
+	Winner  int8      // winner after the move, see Winner
+}
+
+// IsCapture tells whether the move ejected a marble.
+func (r MoveResult) IsCapture() bool {
+	return r.Ejected != nil
+}
+
+// EndedGame tells whether the move ended the game, by a win or a draw.
+func (r MoveResult) EndedGame() bool {
+	return r.Winner != NoWinner
+}
+
+// lastMoveResult describes the last applied move.
+func (g *Game) lastMoveResult() MoveResult {
+	entry := g.history[len(g.history)-1]
+
+	result := MoveResult{
+		Move:   entry.move,
+		Player: entry.player,
+		Moved:  entry.moved,
+		Pushed: entry.pushed,
+		Winner: g.winner,
+	}
+
+	if len(entry.captures) > 0 {
+		capture := entry.captures[len(entry.captures)-1]
+		result.Ejected = &capture
+		result.Score = g.score[capture.Scorer]
+	}
+
+	return result
+}
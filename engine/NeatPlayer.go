@@ -0,0 +1,125 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/yaricom/goNEAT/v4/neat"
+	"github.com/yaricom/goNEAT/v4/neat/network"
+	"math/rand"
+)
+
+/**
+ * NeatPlayer plays the move leading to the state its network scores best.
+ *
+ * The network reads the Features of the state after each valid move and outputs a single score,
+ * from the point of view of the player who just moved.
+ * A network keeps activation state: a NeatPlayer must not choose several moves concurrently.
+ */
+type NeatPlayer struct {
+	Network *network.Network
+	Depth   int  // activation steps ensuring full relaxation of the network
+	Augment bool // average the network output over the symmetries of the board
+}
+
+// NewNeatPlayer wraps a network, computing the activation depth it needs.
+func NewNeatPlayer(phenotype *network.Network) (*NeatPlayer, error) {
+	netDepth, err := phenotype.MaxActivationDepthWithCap(0) // The max depth of the network to be activated
+	if err != nil {
+		neat.WarnLog(fmt.Sprintf(
+			"Failed to estimate maximal depth of the network with loop, using default depth: %d", netDepth))
+	}
+
+	if netDepth == 0 {
+		return nil, errors.New("network depth is zero")
+	}
+
+	return &NeatPlayer{Network: phenotype, Depth: netDepth}, nil
+}
+
+func (p *NeatPlayer) ChooseMove(ctx context.Context, game GameState) (Move, error) {
+	return p.predictSingleMove(game)
+}
+
+func (p *NeatPlayer) predictSingleMove(game GameState) (Move, error) {
+	validMoves := game.GetValidMoves()
+
+	if len(validMoves) == 0 {
+		return nil, fmt.Errorf("no valid moves")
+	}
+
+	bestMoveScore := -1000000.0
+	var bestMove Move
+
+	rand.Shuffle(len(validMoves), func(i, j int) {
+		validMoves[i], validMoves[j] = validMoves[j], validMoves[i]
+	})
+
+	for _, move := range validMoves {
+		nextState := game.Clone()
+		_, err := nextState.Move(move)
+
+		if err != nil {
+			return nil, err
+		}
+
+		score, err := p.evaluateState(nextState)
+		if err != nil {
+			return nil, err
+		}
+
+		//log.Println(fmt.Sprintf("Move: %v, score: %f", move, score))
+
+		if score > bestMoveScore || bestMove == nil {
+			bestMoveScore = score
+			bestMove = move
+			//log.Println(fmt.Sprintf("New best move: %v, score: %f", move, score))
+		}
+	}
+
+	//log.Println(fmt.Sprintf("Best move: %v, score: %f among %d valid moves", bestMove, bestMoveScore, len(validMoves)))
+
+	return bestMove, nil
+}
+
+// evaluateState activates the network on the features of state and returns its output.
+// With Augment, the output is averaged over every symmetric variant of the state.
+func (p *NeatPlayer) evaluateState(state GameState) (float64, error) {
+	variants := []GameState{state}
+
+	if symmetric, ok := state.(SymmetricState); ok && p.Augment {
+		variants = variants[:0]
+		for _, s := range symmetric.Symmetries() {
+			variants = append(variants, symmetric.TransformState(s))
+		}
+	}
+
+	total := 0.0
+
+	for _, variant := range variants {
+		// Set the input values
+		in := variant.Features()
+
+		if err := p.Network.LoadSensors(in); err != nil {
+			neat.ErrorLog(fmt.Sprintf("Failed to load sensors: %s", err))
+			return 0, err
+		}
+
+		// Use depth to ensure full relaxation
+		if success, err := p.Network.ForwardSteps(p.Depth); err != nil || !success {
+			neat.ErrorLog(fmt.Sprintf("Failed to activate network: %s", err))
+			return 0, err
+		}
+
+		// Read output
+		total += p.Network.Outputs[0].Activation
+
+		// Flush network for subsequent use
+		if _, err := p.Network.Flush(); err != nil {
+			neat.ErrorLog(fmt.Sprintf("Failed to flush network: %s", err))
+			return 0, err
+		}
+	}
+
+	return total / float64(len(variants)), nil
+}
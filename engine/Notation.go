@@ -0,0 +1,158 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+/**
+ * Standard abalone notation.
+ *
+ * Cells are named by their row, from A (bottom) to I (top), followed by their diagonal,
+ * from 1 (left) to 9 (right): the bottom left corner is A1, the center is E5.
+ *
+ * Moves are written as:
+ * - inline push: the rear marble of the line and the cell it moves to, e.g. "A1B2"
+ * - broadside: the first and last marbles of the line and the cell the first one moves to, e.g. "A1A3B2"
+ */
+
+// Name returns the standard name of the cell, like "E5".
+func (c Coord3D) Name() string {
+	if !IsValidCoord(c) {
+		return c.String()
+	}
+
+	row := 'A' + rune(BoardRadius-c.Z)
+	diagonal := c.X + BoardRadius + 1
+
+	return fmt.Sprintf("%c%d", row, diagonal)
+}
+
+// Name returns the standard name of the cell, like "E5".
+func (c Coord2D) Name() string {
+	return c.To3D().Name()
+}
+
+// ParseCoord3D reads a cell name like "E5".
+func ParseCoord3D(name string) (Coord3D, error) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+
+	if len(name) != 2 {
+		return Coord3D{}, errors.New(fmt.Sprintf("invalid cell name: %q", name))
+	}
+
+	row := int8(name[0]) - 'A'
+	diagonal := int8(name[1]) - '1'
+
+	z := BoardRadius - row
+	x := diagonal - BoardRadius
+	c := Coord3D{x, -x - z, z}
+
+	if !IsValidCoord(c) {
+		return Coord3D{}, errors.New(fmt.Sprintf("invalid cell name: %q", name))
+	}
+
+	return c, nil
+}
+
+// ParseCoord2D reads a cell name like "E5".
+func ParseCoord2D(name string) (Coord2D, error) {
+	c, err := ParseCoord3D(name)
+	if err != nil {
+		return Coord2D{}, err
+	}
+
+	return c.To2D(), nil
+}
+
+// Notation returns the move in standard notation, like "A1B2".
+func (m PushLine) Notation() string {
+	return m.From.Name() + m.From.Add(m.Direction).Name()
+}
+
+// Notation returns the move in standard notation, like "A1A3B2".
+func (m Broadside) Notation() string {
+	marbles := m.Marbles()
+	return m.From.Name() + marbles[len(marbles)-1].Name() + m.From.Add(m.Direction).Name()
+}
+
+// ParseMove reads an abalone move written in standard notation.
+func ParseMove(notation string) (Move, error) {
+	notation = strings.ToUpper(strings.TrimSpace(notation))
+
+	cells := make([]Coord3D, 0, 3)
+	for i := 0; i+2 <= len(notation); i += 2 {
+		c, err := ParseCoord3D(notation[i : i+2])
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("invalid move %q: %s", notation, err.Error()))
+		}
+		cells = append(cells, c)
+	}
+
+	switch {
+	case len(notation) == 4 && len(cells) == 2:
+		direction, ok := directionBetween(cells[0], cells[1])
+		if !ok {
+			return nil, errors.New(fmt.Sprintf("invalid move %q: cells are not neighbors", notation))
+		}
+
+		return PushLine{From: cells[0], Direction: direction}, nil
+	case len(notation) == 6 && len(cells) == 3:
+		axis, count, ok := lineBetween(cells[0], cells[1])
+		if !ok {
+			return nil, errors.New(fmt.Sprintf("invalid move %q: marbles are not aligned", notation))
+		}
+
+		direction, ok := directionBetween(cells[0], cells[2])
+		if !ok || direction == axis || direction == axis.Opposite() {
+			return nil, errors.New(fmt.Sprintf("invalid move %q: invalid broadside destination", notation))
+		}
+
+		return Broadside{From: cells[0], Axis: axis, Count: count, Direction: direction}.Canonical(), nil
+	default:
+		return nil, errors.New(fmt.Sprintf("invalid move %q: expected 2 or 3 cells", notation))
+	}
+}
+
+// ParseMove reads a move in standard notation, like "A1B2".
+func (g *Game) ParseMove(notation string) (Move, error) {
+	return ParseMove(notation)
+}
+
+// PlayNotation plays a move written in standard notation.
+func (g *Game) PlayNotation(notation string) error {
+	move, err := ParseMove(notation)
+	if err != nil {
+		return err
+	}
+
+	_, err = g.Move(move)
+	return err
+}
+
+// directionBetween returns the direction going from a to its neighbor b.
+func directionBetween(a Coord3D, b Coord3D) (Direction, bool) {
+	for _, direction := range Directions {
+		if a.Add(direction) == b {
+			return direction, true
+		}
+	}
+
+	return 0, false
+}
+
+// lineBetween returns the axis and the number of marbles of the line going from first to last.
+func lineBetween(first Coord3D, last Coord3D) (Direction, int, bool) {
+	for _, direction := range Directions {
+		c := first
+		for count := 2; count <= boardSize; count++ {
+			c = c.Add(direction)
+			if c == last {
+				return direction, count, true
+			}
+		}
+	}
+
+	return 0, 0, false
+}
@@ -0,0 +1,75 @@
+package engine
+
+import (
+	"abalone-go/helpers"
+	"testing"
+)
+
+func TestCellNames(t *testing.T) {
+	helpers.AssertEqual("A1", Coord3D{-4, 0, 4}.Name())
+	helpers.AssertEqual("E5", Coord3D{0, 0, 0}.Name())
+	helpers.AssertEqual("I9", Coord3D{4, 0, -4}.Name())
+
+	for _, c := range boardCoords {
+		parsed, err := ParseCoord3D(c.Name())
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+
+		helpers.AssertEqual(c, parsed)
+	}
+
+	_, err := ParseCoord3D("A6")
+	helpers.AssertEqual(`invalid cell name: "A6"`, err.Error())
+}
+
+func TestParseInlineMove(t *testing.T) {
+	move, err := ParseMove("a1b2")
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(Move(PushLine{From: Coord3D{-4, 0, 4}, Direction: TopRight}), move)
+	helpers.AssertEqual("A1B2", move.String())
+}
+
+func TestParseBroadsideMove(t *testing.T) {
+	move, err := ParseMove("A3A1B4")
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(Move(Broadside{From: Coord3D{-4, 0, 4}, Axis: Right, Count: 3, Direction: TopRight}), move)
+	helpers.AssertEqual("A1A3B2", move.String())
+}
+
+func TestEveryValidMoveRoundTrips(t *testing.T) {
+	game, err := NewGameFromLayout(GermanDaisyLayout)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	for _, move := range game.GetValidMoves() {
+		parsed, err := ParseMove(move.String())
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+
+		helpers.AssertEqual(move, parsed)
+	}
+}
+
+func TestPlayNotation(t *testing.T) {
+	game, err := NewGameFromLayout(StandardLayout)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	err = game.PlayNotation("A1B2")
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(int8(0), game.GetGrid(Coord3D{-4, 0, 4}))
+	helpers.AssertEqual(int8(1), game.GetGrid(Coord3D{-1, 0, 1}))
+}
@@ -0,0 +1,121 @@
+package engine
+
+import (
+	"log"
+)
+
+/**
+ * GameObserver is notified of the events of a Game.
+ *
+ * Events are sent once the move is fully applied, in this order:
+ * - OnMove for the move
+ * - OnCapture for each marble ejected by the move
+ * - OnGameOver if the move ended the game, by a win or a draw
+ *
+ * Observers are called synchronously, by the goroutine playing the move.
+ * Undo does not send any event, Redo sends the events of the replayed move.
+ */
+type GameObserver interface {
+	OnMove(game *Game, move Move)
+	OnCapture(game *Game, capture Capture)
+	OnGameOver(game *Game, winner int8)
+}
+
+// Capture is a marble pushed out of the hexagon.
+type Capture struct {
+	At     Coord3D // last cell of the marble on the board
+	Owner  int8    // player owning the marble
+	Scorer int8    // player credited with the capture
+}
+
+// AddObserver registers an observer, notified of the events of the game until it is removed.
+// Copies of the game do not inherit the observers.
+func (g *Game) AddObserver(observer GameObserver) {
+	g.observers = append(g.observers, observer)
+}
+
+// RemoveObserver unregisters an observer added with AddObserver.
+func (g *Game) RemoveObserver(observer GameObserver) {
+	for i, o := range g.observers {
+		if o == observer {
+			g.observers = append(g.observers[:i:i], g.observers[i+1:]...)
+			return
+		}
+	}
+}
+
+// notifyObservers sends the events of the last applied move.
+func (g *Game) notifyObservers() {
+	if len(g.observers) == 0 {
+		return
+	}
+
+	entry := g.history[len(g.history)-1]
+
+	for _, observer := range g.observers {
+		observer.OnMove(g, entry.move)
+	}
+
+	for _, capture := range entry.captures {
+		for _, observer := range g.observers {
+			observer.OnCapture(g, capture)
+		}
+	}
+
+	if g.IsOver() {
+		for _, observer := range g.observers {
+			observer.OnGameOver(g, g.winner)
+		}
+	}
+}
+
+/**
+ * ObserverFuncs is a GameObserver calling the functions it is given. Missing functions are skipped.
+ */
+type ObserverFuncs struct {
+	Move     func(game *Game, move Move)
+	Capture  func(game *Game, capture Capture)
+	GameOver func(game *Game, winner int8)
+}
+
+func (o *ObserverFuncs) OnMove(game *Game, move Move) {
+	if o.Move != nil {
+		o.Move(game, move)
+	}
+}
+
+func (o *ObserverFuncs) OnCapture(game *Game, capture Capture) {
+	if o.Capture != nil {
+		o.Capture(game, capture)
+	}
+}
+
+func (o *ObserverFuncs) OnGameOver(game *Game, winner int8) {
+	if o.GameOver != nil {
+		o.GameOver(game, winner)
+	}
+}
+
+/**
+ * LogObserver logs the events of a game with the standard logger.
+ */
+type LogObserver struct {
+	Prefix string
+}
+
+func (o *LogObserver) OnMove(game *Game, move Move) {
+	log.Printf("%sTurn %d: player %d played %v", o.Prefix, game.Turn, game.history[len(game.history)-1].player, move)
+}
+
+func (o *LogObserver) OnCapture(game *Game, capture Capture) {
+	log.Printf("%sPlayer %d ejected a marble of player %d at %s, captures: %d", o.Prefix, capture.Scorer, capture.Owner, capture.At.Name(), game.Score(capture.Scorer))
+}
+
+func (o *LogObserver) OnGameOver(game *Game, winner int8) {
+	if winner == Draw {
+		log.Printf("%sGame over after %d turns: draw", o.Prefix, game.Turn)
+		return
+	}
+
+	log.Printf("%sGame over after %d turns: player %d won", o.Prefix, game.Turn, winner)
+}
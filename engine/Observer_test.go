@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"abalone-go/helpers"
+	"testing"
+)
+
+type recordingObserver struct {
+	events []string
+}
+
+func (o *recordingObserver) OnMove(game *Game, move Move) {
+	o.events = append(o.events, "move "+move.String())
+}
+
+func (o *recordingObserver) OnCapture(game *Game, capture Capture) {
+	o.events = append(o.events, "capture "+capture.At.Name())
+}
+
+func (o *recordingObserver) OnGameOver(game *Game, winner int8) {
+	o.events = append(o.events, "over")
+}
+
+func TestObserversAreNotifiedOfMovesCapturesAndGameOver(t *testing.T) {
+	game := NewGame(&emptyGrid)
+	game.SetGrid(Coord3D{2, -2, 0}, 1)
+	game.SetGrid(Coord3D{3, -3, 0}, 1)
+	game.SetGrid(Coord3D{4, -4, 0}, 2)
+	game.score[1] = DefaultMarblesToWin - 1
+
+	first := &recordingObserver{}
+	second := &recordingObserver{}
+	winners := make([]int8, 0)
+
+	game.AddObserver(first)
+	game.AddObserver(second)
+	game.AddObserver(&ObserverFuncs{GameOver: func(game *Game, winner int8) {
+		winners = append(winners, winner)
+	}})
+
+	if err := game.Push(Coord3D{2, -2, 0}, Right); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual([]string{"move E7E8", "capture E9", "over"}, first.events)
+	helpers.AssertEqual(first.events, second.events)
+	helpers.AssertEqual([]int8{1}, winners)
+}
+
+func TestRemovedObserverIsNotNotified(t *testing.T) {
+	game := NewGame(&startingGrid)
+
+	kept := &recordingObserver{}
+	removed := &recordingObserver{}
+
+	game.AddObserver(kept)
+	game.AddObserver(removed)
+	game.RemoveObserver(removed)
+
+	if err := game.PlayNotation("A1B1"); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual([]string{"move A1B1"}, kept.events)
+	helpers.AssertEqual(0, len(removed.events))
+
+	// copies used by the search must not notify the game observers
+	if err := game.Copy().PlayNotation("I5H5"); err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(1, len(kept.events))
+}
@@ -0,0 +1,214 @@
+package engine
+
+import (
+	"abalone-go/helpers"
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+/**
+ * Player chooses the moves of one side of a game.
+ *
+ * Any mix of players can play together with PlayGame: random players, NEAT networks,
+ * search players or humans typing their moves.
+ */
+type Player interface {
+	// ChooseMove returns the move to play in game, which is not over. The game must not be modified.
+	ChooseMove(ctx context.Context, game GameState) (Move, error)
+}
+
+// PlayGame plays game until it is over, players[i] choosing the moves of player i+1.
+// It returns the number of moves played.
+func PlayGame(ctx context.Context, game GameState, players []Player) (int, error) {
+	turn := 0
+
+	for !game.IsOver() {
+		if err := ctx.Err(); err != nil {
+			return turn, err
+		}
+
+		if len(game.GetValidMoves()) == 0 {
+			break
+		}
+
+		current := int(game.CurrentPlayer())
+		if current > len(players) {
+			return turn, errors.New(fmt.Sprintf("no player for player %d", current))
+		}
+
+		move, err := players[current-1].ChooseMove(ctx, game)
+		if err != nil {
+			return turn, err
+		}
+
+		if _, err := game.Move(move); err != nil {
+			return turn, errors.New(fmt.Sprintf("invalid move %v of player %d: %s", move, current, err.Error()))
+		}
+
+		turn++
+	}
+
+	return turn, nil
+}
+
+/**
+ * RandomPlayer plays any valid move.
+ */
+type RandomPlayer struct{}
+
+func (p *RandomPlayer) ChooseMove(ctx context.Context, game GameState) (Move, error) {
+	moves := game.GetValidMoves()
+	if len(moves) == 0 {
+		return nil, errors.New("no valid moves")
+	}
+
+	return helpers.RandIn(moves), nil
+}
+
+/**
+ * GreedyPlayer looks one move ahead: it plays a winning move if there is one,
+ * otherwise a capture, otherwise any valid move.
+ */
+type GreedyPlayer struct{}
+
+func (p *GreedyPlayer) ChooseMove(ctx context.Context, game GameState) (Move, error) {
+	moves := game.GetValidMoves()
+	if len(moves) == 0 {
+		return nil, errors.New("no valid moves")
+	}
+
+	captures := make([]Move, 0)
+
+	for _, move := range moves {
+		result, err := game.Clone().Move(move)
+		if err != nil {
+			return nil, err
+		}
+
+		if result.Winner == result.Player {
+			return move, nil
+		}
+
+		if result.IsCapture() && result.Ejected.Scorer == result.Player {
+			captures = append(captures, move)
+		}
+	}
+
+	if len(captures) > 0 {
+		return helpers.RandIn(captures), nil
+	}
+
+	return helpers.RandIn(moves), nil
+}
+
+/**
+ * MoveParser is a game reading the moves typed by humans.
+ */
+type MoveParser interface {
+	ParseMove(notation string) (Move, error)
+}
+
+/**
+ * HumanPlayer reads the moves from a text input, one per line.
+ *
+ * The game is shown on the output before each move. Invalid moves are rejected and asked again,
+ * "moves" lists the valid moves.
+ *
+ * The input is read in the background, so that a cancelled context stops the wait for a move.
+ */
+type HumanPlayer struct {
+	in  *bufio.Scanner
+	out io.Writer
+
+	startReading sync.Once
+	lines        chan string // lines of the input, closed at its end
+	err          error       // error ending the input, set before lines is closed
+}
+
+func NewHumanPlayer(in io.Reader, out io.Writer) *HumanPlayer {
+	return &HumanPlayer{in: bufio.NewScanner(in), out: out}
+}
+
+func (p *HumanPlayer) ChooseMove(ctx context.Context, game GameState) (Move, error) {
+	parser, ok := game.(MoveParser)
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("cannot read moves of %T", game))
+	}
+
+	if shown, ok := game.(interface{ Show() string }); ok {
+		fmt.Fprint(p.out, shown.Show())
+	}
+
+	validMoves := game.GetValidMoves()
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		fmt.Fprintf(p.out, "Player %d, your move: ", game.CurrentPlayer())
+
+		line, err := p.readLine(ctx)
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimSpace(line)
+
+		if line == "moves" {
+			for _, move := range validMoves {
+				fmt.Fprintf(p.out, "%v\n", move)
+			}
+			continue
+		}
+
+		move, err := parser.ParseMove(line)
+		if err != nil {
+			fmt.Fprintln(p.out, err.Error())
+			continue
+		}
+
+		for _, valid := range validMoves {
+			if valid.Equal(move) {
+				return valid, nil
+			}
+		}
+
+		fmt.Fprintf(p.out, "move %v is not valid\n", move)
+	}
+}
+
+// readLine waits for the next line of the input, or for ctx to be done.
+func (p *HumanPlayer) readLine(ctx context.Context) (string, error) {
+	p.startReading.Do(func() {
+		p.lines = make(chan string)
+		go p.read()
+	})
+
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case line, ok := <-p.lines:
+		if !ok {
+			if p.err != nil {
+				return "", p.err
+			}
+			return "", io.EOF
+		}
+		return line, nil
+	}
+}
+
+// read sends the lines of the input to lines until its end.
+func (p *HumanPlayer) read() {
+	for p.in.Scan() {
+		p.lines <- p.in.Text()
+	}
+
+	p.err = p.in.Err()
+	close(p.lines)
+}
@@ -0,0 +1,96 @@
+package engine
+
+import (
+	"abalone-go/helpers"
+	"bytes"
+	"context"
+	"github.com/yaricom/goNEAT/v4/neat/network"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPlayGameBetweenRandomPlayers(t *testing.T) {
+	game := NewTicTacToe()
+
+	turns, err := PlayGame(context.Background(), game, []Player{&RandomPlayer{}, &RandomPlayer{}})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(true, game.IsOver())
+	helpers.AssertEqual(game.Turn, turns)
+}
+
+func TestGreedyPlayerPlaysTheWinningMove(t *testing.T) {
+	game := NewGame(&emptyGrid)
+	game.SetGrid(Coord3D{0, 0, 0}, 1)
+	game.SetGrid(Coord3D{2, -2, 0}, 1)
+	game.SetGrid(Coord3D{3, -3, 0}, 1)
+	game.SetGrid(Coord3D{4, -4, 0}, 2)
+	game.score[1] = DefaultMarblesToWin - 1
+
+	move, err := (&GreedyPlayer{}).ChooseMove(context.Background(), game)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(PushLine{From: Coord3D{2, -2, 0}, Direction: Right}, move)
+}
+
+func TestNeatPlayerPlaysTheBestScoredMove(t *testing.T) {
+	game := NewTicTacToe()
+
+	// the network only rewards a stone of the first player in the center cell
+	inputs := make([]*network.NNode, 0, 18)
+	for i := 0; i < 18; i++ {
+		inputs = append(inputs, network.NewNNode(i+1, network.InputNeuron))
+	}
+	output := network.NewNNode(19, network.OutputNeuron)
+	output.ConnectFrom(inputs[2*4], 5.0)
+
+	player, err := NewNeatPlayer(network.NewNetwork(inputs, []*network.NNode{output}, append(inputs, output), 0))
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		move, err := player.ChooseMove(context.Background(), game)
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+
+		helpers.AssertEqual(Placement{At: Coord2D{X: 1, Y: 1}}, move)
+	}
+}
+
+func TestHumanPlayerRetriesInvalidMoves(t *testing.T) {
+	game := NewGame(&startingGrid)
+	out := &bytes.Buffer{}
+
+	player := NewHumanPlayer(strings.NewReader("Z9\nA1A2\na1b1\n"), out)
+
+	move, err := player.ChooseMove(context.Background(), game)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(PushLine{From: Coord3D{-4, 0, 4}, Direction: TopLeft}, move)
+	helpers.AssertEqual(true, strings.Contains(out.String(), "invalid cell name"))
+	helpers.AssertEqual(true, strings.Contains(out.String(), "move A1A2 is not valid"))
+
+	_, err = player.ChooseMove(context.Background(), game)
+	helpers.AssertEqual(true, err != nil)
+}
+
+func TestHumanPlayerStopsWaitingWhenCancelled(t *testing.T) {
+	in, _ := io.Pipe()
+	player := NewHumanPlayer(in, io.Discard)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	_, err := player.ChooseMove(ctx, NewGame(&startingGrid))
+	helpers.AssertEqual(context.Canceled, err)
+}
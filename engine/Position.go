@@ -0,0 +1,177 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+/**
+ * Position strings describe a full game state on a single line, like FEN for chess:
+ *
+ *   <rows> <side to move> <turn> <captures>
+ *
+ * - rows: the rows of the hexagon from the top (I) to the bottom (A), separated by "/".
+ *   Each row lists its cells from left to right: a player letter for a marble,
+ *   a digit for a run of empty cells.
+ * - side to move: the letter of the current player
+ * - turn: the number of moves played so far
+ * - captures: the number of enemy marbles ejected by each player, separated by "-".
+ *   Their count gives the number of players.
+ *
+ * Player letters are "w" for player 1 (white), "b" for player 2 (black), "u" for player 3 (blue),
+ * then "r", "g" and "y".
+ *
+ * The standard starting position is:
+ *
+ *   bbbbb/bbbbbb/2bbb2/8/9/8/2www2/wwwwww/wwwww w 0 0-0
+ */
+
+var playerLetters = [MaxPlayers + 1]byte{0, 'w', 'b', 'u', 'r', 'g', 'y'}
+
+func (g *Game) String() string {
+	rows, side, captures := g.positionFields()
+	return fmt.Sprintf("%s %s %d %s", rows, side, g.Turn, captures)
+}
+
+func (g *Game) positionFields() (string, string, string) {
+	rows := make([]string, 0, boardSize)
+
+	for z := int8(-BoardRadius); z <= BoardRadius; z++ {
+		row := ""
+		empty := 0
+
+		for _, c := range rowCoords(z) {
+			v := g.grid.get(c)
+			if v == 0 {
+				empty++
+				continue
+			}
+
+			if empty > 0 {
+				row += strconv.Itoa(empty)
+				empty = 0
+			}
+			row += string(playerLetters[v])
+		}
+
+		if empty > 0 {
+			row += strconv.Itoa(empty)
+		}
+
+		rows = append(rows, row)
+	}
+
+	captures := make([]string, 0, g.rules.Players)
+	for player := 1; player <= int(g.rules.Players); player++ {
+		captures = append(captures, strconv.Itoa(int(g.score[player])))
+	}
+
+	return strings.Join(rows, "/"), string(playerLetters[g.currentPlayer]), strings.Join(captures, "-")
+}
+
+// ParsePosition reads a game written as a position string.
+func ParsePosition(position string) (*Game, error) {
+	fields := strings.Fields(position)
+	if len(fields) != 4 {
+		return nil, errors.New(fmt.Sprintf("invalid position: expected 4 fields, got %d", len(fields)))
+	}
+
+	grid, err := parsePositionRows(fields[0])
+	if err != nil {
+		return nil, err
+	}
+
+	captures := strings.Split(fields[3], "-")
+	if len(captures) < 2 || len(captures) > MaxPlayers {
+		return nil, errors.New(fmt.Sprintf("invalid position: expected 2 to %d captures, got %d", MaxPlayers, len(captures)))
+	}
+
+	rules := DefaultRules()
+	rules.Players = int8(len(captures))
+
+	for _, c := range boardCoords {
+		if grid.get(c) > rules.Players {
+			return nil, errors.New(fmt.Sprintf("invalid position: marble of player %d in a %d players game", grid.get(c), rules.Players))
+		}
+	}
+
+	game := newGameWithRules(&grid, rules)
+
+	if len(fields[1]) != 1 || playerOfLetter(fields[1][0]) == 0 || playerOfLetter(fields[1][0]) > rules.Players {
+		return nil, errors.New(fmt.Sprintf("invalid position: unknown side to move %q", fields[1]))
+	}
+	game.currentPlayer = playerOfLetter(fields[1][0])
+
+	game.Turn, err = strconv.Atoi(fields[2])
+	if err != nil || game.Turn < 0 {
+		return nil, errors.New(fmt.Sprintf("invalid position: invalid turn %q", fields[2]))
+	}
+
+	for i, capture := range captures {
+		player := int8(i + 1)
+
+		v, err := strconv.ParseInt(capture, 10, 8)
+		if err != nil || v < 0 {
+			return nil, errors.New(fmt.Sprintf("invalid position: invalid captures %q", capture))
+		}
+
+		game.score[player] = int8(v)
+		if game.score[player] >= game.rules.MarblesToWin {
+			game.winner = player
+		}
+	}
+
+	return game, nil
+}
+
+func parsePositionRows(field string) (Grid, error) {
+	grid := buildEmptyGrid()
+
+	rows := strings.Split(field, "/")
+	if len(rows) != boardSize {
+		return grid, errors.New(fmt.Sprintf("invalid position: expected %d rows, got %d", boardSize, len(rows)))
+	}
+
+	for i, row := range rows {
+		coords := rowCoords(int8(i - BoardRadius))
+		cell := 0
+
+		for j := 0; j < len(row); j++ {
+			ch := row[j]
+
+			if ch >= '1' && ch <= '9' {
+				cell += int(ch - '0')
+				continue
+			}
+
+			player := playerOfLetter(ch)
+			if player == 0 {
+				return grid, errors.New(fmt.Sprintf("invalid position: unknown cell %q in row %d", ch, i+1))
+			}
+
+			if cell < len(coords) {
+				grid.set(coords[cell], player)
+			}
+			cell++
+		}
+
+		if cell != len(coords) {
+			return grid, errors.New(fmt.Sprintf("invalid position: expected %d cells in row %d, got %d", len(coords), i+1, cell))
+		}
+	}
+
+	return grid, nil
+}
+
+// playerOfLetter returns the player written as letter, or 0 if there is none.
+func playerOfLetter(letter byte) int8 {
+	for player, l := range playerLetters {
+		if player > 0 && l == letter {
+			return int8(player)
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,38 @@
+package engine
+
+import (
+	"abalone-go/helpers"
+	"testing"
+)
+
+func TestStandardPosition(t *testing.T) {
+	game, err := NewGameFromLayout(StandardLayout)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual("bbbbb/bbbbbb/2bbb2/8/9/8/2www2/wwwwww/wwwww w 0 0-0", game.String())
+}
+
+func TestPositionRoundTrip(t *testing.T) {
+	position := "5/6/7/8/3w1wwb1/8/7/6/5 b 12 3-5"
+
+	game, err := ParsePosition(position)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(int8(2), game.currentPlayer)
+	helpers.AssertEqual(12, game.Turn)
+	helpers.AssertEqual(int8(3), game.Score(1))
+	helpers.AssertEqual(int8(5), game.Score(2))
+	helpers.AssertEqual(int8(1), game.GetGrid(Coord3D{-1, 1, 0}))
+	helpers.AssertEqual(int8(2), game.GetGrid(Coord3D{3, -3, 0}))
+	helpers.AssertEqual(position, game.String())
+}
+
+func TestInvalidPosition(t *testing.T) {
+	_, err := ParsePosition("5/6/7/8/55/8/7/6/5 w 0 0-0")
+
+	helpers.AssertEqual("invalid position: expected 9 cells in row 5, got 10", err.Error())
+}
@@ -0,0 +1,172 @@
+package engine
+
+import (
+	"abalone-go/helpers"
+	"fmt"
+	"strings"
+)
+
+/**
+ * Board rendering.
+ *
+ * The hexagon is drawn with its row letters on the left and its diagonal numbers
+ * along the bottom right edge, like a physical board:
+ *
+ *     I  b b b b b
+ *    H  b b b b b b
+ *   G  . . b b b . .
+ *  F  . . . . . . . .
+ * E  . . . . . . . . .
+ *  D  . . . . . . . . 9
+ *   C  . . w w w . . 8
+ *    B  w w w w w w 7
+ *     A  w w w w w 6
+ *         1 2 3 4 5
+ *
+ * Each cell is drawn as a marker followed by a symbol. The marker is "*" for a cell
+ * changed by the last move and "!" for a marble that can be ejected at the next turn.
+ */
+
+type RenderMode int8
+
+const (
+	RenderASCII   RenderMode = iota // player letters, for logs
+	RenderUnicode                   // marble symbols, for terminals without colors
+	RenderColor                     // colored marbles with ANSI escape codes
+)
+
+type RenderOptions struct {
+	Mode              RenderMode
+	HighlightLastMove bool // mark the cells changed by the last move
+	HighlightDanger   bool // mark the marbles that can be ejected at the next turn
+}
+
+var unicodeSymbols = [MaxPlayers + 1]string{"·", "○", "●", "◆", "■", "▲", "★"}
+
+var ansiColors = [MaxPlayers + 1]string{"2", "97", "90", "34", "31", "32", "33"}
+
+const ansiReset = "\033[0m"
+
+// DefaultRenderOptions are the options used by Show: plain text with the last move highlighted.
+func DefaultRenderOptions() RenderOptions {
+	return RenderOptions{
+		Mode:              RenderASCII,
+		HighlightLastMove: true,
+		HighlightDanger:   false,
+	}
+}
+
+// Render draws the board as a hexagon.
+func (g *Game) Render(options RenderOptions) string {
+	lastMove := make(map[Coord3D]bool)
+	if options.HighlightLastMove && len(g.history) > 0 {
+		for _, change := range g.history[len(g.history)-1].cells {
+			lastMove[change.at] = true
+		}
+	}
+
+	danger := make(map[Coord3D]bool)
+	if options.HighlightDanger {
+		danger = g.threatenedMarbles()
+	}
+
+	res := ""
+
+	for z := int8(-BoardRadius); z <= BoardRadius; z++ {
+		coords := rowCoords(z)
+		row := BoardRadius - z
+
+		res += strings.Repeat(" ", int(helpers.Abs(z))) + string(rune('A'+row)) + " "
+
+		for _, c := range coords {
+			marker := " "
+			if danger[c] {
+				marker = "!"
+			} else if lastMove[c] {
+				marker = "*"
+			}
+
+			res += renderMarker(marker, options.Mode) + renderCell(g.grid.get(c), options.Mode)
+		}
+
+		// the diagonal leaving the board below the last cell of the row
+		if z > 0 {
+			res += fmt.Sprintf(" %d", coords[len(coords)-1].X+BoardRadius+2)
+		}
+
+		res += "\n"
+	}
+
+	res += strings.Repeat(" ", BoardRadius+3)
+	for diagonal := 1; diagonal <= BoardRadius+1; diagonal++ {
+		res += fmt.Sprintf(" %d", diagonal)
+	}
+	res += "\n"
+
+	return res
+}
+
+func renderCell(v int8, mode RenderMode) string {
+	switch mode {
+	case RenderUnicode:
+		return unicodeSymbols[v]
+	case RenderColor:
+		symbol := "●"
+		if v == 0 {
+			symbol = unicodeSymbols[0]
+		}
+		return "\033[" + ansiColors[v] + "m" + symbol + ansiReset
+	default:
+		if v == 0 {
+			return "."
+		}
+		return string(playerLetters[v])
+	}
+}
+
+func renderMarker(marker string, mode RenderMode) string {
+	if mode != RenderColor || marker == " " {
+		return marker
+	}
+
+	color := "33"
+	if marker == "!" {
+		color = "31"
+	}
+
+	return "\033[1;" + color + "m" + marker + ansiReset
+}
+
+// threatenedMarbles lists the marbles that a player could eject at their next turn.
+func (g *Game) threatenedMarbles() map[Coord3D]bool {
+	threatened := make(map[Coord3D]bool)
+	if g.IsOver() {
+		return threatened
+	}
+
+	for player := int8(1); player <= g.rules.Players; player++ {
+		for _, from := range boardCoords {
+			if g.grid.get(from) != player {
+				continue
+			}
+
+			for _, direction := range Directions {
+				mine, enemies, err := g.checkCanPushFor(player, from, direction)
+				if err != nil || enemies == 0 {
+					continue
+				}
+
+				last := from
+				for i := 0; i < mine+enemies-1; i++ {
+					last = last.Add(direction)
+				}
+
+				if !IsValidCoord(last.Add(direction)) {
+					threatened[last] = true
+				}
+			}
+		}
+	}
+
+	return threatened
+}
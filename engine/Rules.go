@@ -0,0 +1,88 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Rules are the parameters of an abalone variant.
+type Rules struct {
+	Players           int8   // number of players, from 2 to MaxPlayers
+	MarblesToWin      int8   // ejected enemy marbles needed to win the game
+	MaxGroupSize      int    // maximum number of marbles moving together
+	AllowSelfEjection bool   // whether a player may push their own marbles out of the hexagon
+	MaxTurns          int    // moves after which the game is a draw, 0 for no limit
+	Layout            string // name of the starting layout, see RegisterLayout
+}
+
+// MaxPlayers is the maximum number of players of a game.
+const MaxPlayers = 6
+
+// DefaultMarblesToWin is the number of ejected enemy marbles needed to win a standard game.
+const DefaultMarblesToWin = 6
+
+// DefaultMaxGroupSize is the maximum number of marbles moving together in a standard game.
+const DefaultMaxGroupSize = 3
+
+// DefaultRules returns the rules of a standard game.
+func DefaultRules() Rules {
+	return Rules{
+		Players:           2,
+		MarblesToWin:      DefaultMarblesToWin,
+		MaxGroupSize:      DefaultMaxGroupSize,
+		AllowSelfEjection: false,
+		MaxTurns:          0,
+		Layout:            StandardLayout,
+	}
+}
+
+// Validate checks that the rules describe a playable game.
+func (r Rules) Validate() error {
+	if r.Players < 2 || r.Players > MaxPlayers {
+		return errors.New(fmt.Sprintf("invalid rules: players must be between 2 and %d, got %d", MaxPlayers, r.Players))
+	}
+
+	if r.MarblesToWin < 1 {
+		return errors.New(fmt.Sprintf("invalid rules: marbles to win must be positive, got %d", r.MarblesToWin))
+	}
+
+	if r.MaxGroupSize < 1 || r.MaxGroupSize > boardSize {
+		return errors.New(fmt.Sprintf("invalid rules: max group size must be between 1 and %d, got %d", boardSize, r.MaxGroupSize))
+	}
+
+	if r.MaxTurns < 0 {
+		return errors.New(fmt.Sprintf("invalid rules: max turns must not be negative, got %d", r.MaxTurns))
+	}
+
+	return nil
+}
+
+// ThreePlayerRules returns the rules of the three players variant:
+// 11 marbles each on the standard hexagon, the first player to eject 6 marbles wins.
+func ThreePlayerRules() Rules {
+	rules := DefaultRules()
+	rules.Players = 3
+	rules.Layout = ThreePlayerLayout
+
+	return rules
+}
+
+// NewGameFromRules starts a game following rules on their starting layout.
+func NewGameFromRules(rules Rules) (*Game, error) {
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+
+	grid, err := GetLayout(rules.Layout)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range boardCoords {
+		if grid.get(c) > rules.Players {
+			return nil, errors.New(fmt.Sprintf("layout %s has marbles of player %d, the game has only %d players", rules.Layout, grid.get(c), rules.Players))
+		}
+	}
+
+	return newGameWithRules(&grid, rules), nil
+}
@@ -0,0 +1,262 @@
+package engine
+
+import (
+	"fmt"
+)
+
+/**
+ * Board symmetries.
+ *
+ * A symmetry optionally mirrors the board left to right, then rotates it clockwise:
+ * - the hexagon has 6 rotations of 60 degrees, so 12 symmetries
+ * - the square grid has 4 rotations of 90 degrees, so 8 symmetries
+ *
+ * Transforming a position and its moves by a same symmetry gives an equivalent game.
+ * The canonical form of a position is the transformed position that comes first among all of them,
+ * so that equivalent positions share it. It can deduplicate opening books or training data.
+ */
+
+type Symmetry struct {
+	Rotation int  // clockwise rotations: 60 degrees on the hexagon, 90 degrees on the square grid
+	Reflect  bool // mirror left to right before rotating
+}
+
+// Number of rotations bringing each board back to itself.
+const (
+	HexRotations    = 6
+	SquareRotations = 4
+)
+
+var HexSymmetries = buildSymmetries(HexRotations)
+var SquareSymmetries = buildSymmetries(SquareRotations)
+
+// Identity leaves the board unchanged.
+var Identity = Symmetry{}
+
+func buildSymmetries(rotations int) []Symmetry {
+	symmetries := make([]Symmetry, 0, rotations*2)
+
+	for _, reflect := range []bool{false, true} {
+		for rotation := 0; rotation < rotations; rotation++ {
+			symmetries = append(symmetries, Symmetry{Rotation: rotation, Reflect: reflect})
+		}
+	}
+
+	return symmetries
+}
+
+func (s Symmetry) String() string {
+	if s.Reflect {
+		return fmt.Sprintf("Reflect+Rotate(%d)", s.Rotation)
+	}
+
+	return fmt.Sprintf("Rotate(%d)", s.Rotation)
+}
+
+// Inverse returns the symmetry undoing s on a board with the given number of rotations.
+// A mirrored symmetry is its own inverse.
+func (s Symmetry) Inverse(rotations int) Symmetry {
+	if s.Reflect {
+		return s
+	}
+
+	return Symmetry{Rotation: (rotations - s.Rotation) % rotations}
+}
+
+/**
+ * SymmetricState is a GameState whose board has symmetries.
+ */
+type SymmetricState interface {
+	GameState
+
+	// Symmetries lists the symmetries of the board, Identity first.
+	Symmetries() []Symmetry
+
+	// TransformState returns a copy of the position transformed by s.
+	TransformState(s Symmetry) GameState
+
+	// TransformMove returns the move transformed by s, in its canonical description.
+	TransformMove(move Move, s Symmetry) Move
+}
+
+var _ SymmetricState = (*Game)(nil)
+var _ SymmetricState = (*MNKGame)(nil)
+
+// TransformCoord returns the hexagon cell c transformed by s.
+func (s Symmetry) TransformCoord(c Coord3D) Coord3D {
+	if s.Reflect {
+		c = Coord3D{c.Y, c.X, c.Z}
+	}
+
+	for i := 0; i < s.Rotation; i++ {
+		c = Coord3D{-c.Z, -c.X, -c.Y}
+	}
+
+	return c
+}
+
+// TransformDirection returns the hexagon direction d transformed by s.
+func (s Symmetry) TransformDirection(d Direction) Direction {
+	i := int(d)
+	if s.Reflect {
+		i = len(Directions) - 1 - i
+	}
+
+	return Directions[(i+s.Rotation)%len(Directions)]
+}
+
+// TransformMove returns the abalone move transformed by s, in its canonical description.
+func (s Symmetry) TransformMove(move Move) Move {
+	switch m := move.(type) {
+	case PushLine:
+		return PushLine{From: s.TransformCoord(m.From), Direction: s.TransformDirection(m.Direction)}
+	case Broadside:
+		return Broadside{
+			From:      s.TransformCoord(m.From),
+			Axis:      s.TransformDirection(m.Axis),
+			Count:     m.Count,
+			Direction: s.TransformDirection(m.Direction),
+		}.Canonical()
+	default:
+		panic(fmt.Sprintf("Invalid move type %T", move))
+	}
+}
+
+func (g *Game) Symmetries() []Symmetry {
+	return HexSymmetries
+}
+
+// Transform returns the position transformed by s.
+// The moves history is not transformed: the new game starts from this position.
+func (g *Game) Transform(s Symmetry) *Game {
+	grid := buildEmptyGrid()
+	for _, c := range boardCoords {
+		grid.set(s.TransformCoord(c), g.grid.get(c))
+	}
+
+	newGame := newGameWithRules(&grid, g.rules)
+	newGame.currentPlayer = g.currentPlayer
+	newGame.score = g.score
+	newGame.Turn = g.Turn
+	newGame.winner = g.winner
+
+	return newGame
+}
+
+func (g *Game) TransformState(s Symmetry) GameState {
+	return g.Transform(s)
+}
+
+func (g *Game) TransformMove(move Move, s Symmetry) Move {
+	return s.TransformMove(move)
+}
+
+// Canonical returns the canonical form of the position and the symmetry leading to it.
+func (g *Game) Canonical() (*Game, Symmetry) {
+	best := g.Transform(Identity)
+	bestSymmetry := Identity
+	bestKey := best.String()
+
+	for _, s := range HexSymmetries[1:] {
+		transformed := g.Transform(s)
+		if key := transformed.String(); key < bestKey {
+			best, bestSymmetry, bestKey = transformed, s, key
+		}
+	}
+
+	return best, bestSymmetry
+}
+
+// Symmetries lists the symmetries of the grid: all 8 on a square grid,
+// but only those keeping the width and height on a rectangular one.
+func (g *MNKGame) Symmetries() []Symmetry {
+	if g.Width == g.Height {
+		return SquareSymmetries
+	}
+
+	symmetries := make([]Symmetry, 0, len(SquareSymmetries)/2)
+	for _, s := range SquareSymmetries {
+		if s.Rotation%2 == 0 {
+			symmetries = append(symmetries, s)
+		}
+	}
+
+	return symmetries
+}
+
+// transformCoord returns the cell c transformed by s, with the width and height of the transformed grid.
+func (g *MNKGame) transformCoord(c Coord2D, s Symmetry) (Coord2D, int, int) {
+	width, height := g.Width, g.Height
+
+	if s.Reflect {
+		c = Coord2D{int8(width-1) - c.X, c.Y}
+	}
+
+	for i := 0; i < s.Rotation; i++ {
+		c = Coord2D{int8(height-1) - c.Y, c.X}
+		width, height = height, width
+	}
+
+	return c, width, height
+}
+
+// Transform returns the position transformed by s. A quarter turn swaps the width and height of the grid.
+// As for Game.Transform, the moves history is not transformed.
+func (g *MNKGame) Transform(s Symmetry) *MNKGame {
+	_, width, height := g.transformCoord(Coord2D{}, s)
+
+	newGame := *g
+	newGame.Width = width
+	newGame.Height = height
+	newGame.grid = make([]int8, len(g.grid))
+	newGame.history = nil
+
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			c, _, _ := g.transformCoord(Coord2D{int8(x), int8(y)}, s)
+			newGame.SetGrid(c, g.grid[y*g.Width+x])
+		}
+	}
+
+	return &newGame
+}
+
+func (g *MNKGame) TransformState(s Symmetry) GameState {
+	return g.Transform(s)
+}
+
+func (g *MNKGame) TransformMove(move Move, s Symmetry) Move {
+	placement, ok := move.(Placement)
+	if !ok {
+		panic(fmt.Sprintf("Invalid move type %T", move))
+	}
+
+	at, _, _ := g.transformCoord(placement.At, s)
+	return Placement{At: at}
+}
+
+// Canonical returns the canonical form of the position and the symmetry leading to it.
+func (g *MNKGame) Canonical() (*MNKGame, Symmetry) {
+	best := g.Transform(Identity)
+	bestSymmetry := Identity
+
+	for _, s := range g.Symmetries()[1:] {
+		transformed := g.Transform(s)
+		if compareGrids(transformed.grid, best.grid) < 0 {
+			best, bestSymmetry = transformed, s
+		}
+	}
+
+	return best, bestSymmetry
+}
+
+// compareGrids orders grids cell by cell.
+func compareGrids(a []int8, b []int8) int {
+	for i := range a {
+		if a[i] != b[i] {
+			return int(a[i]) - int(b[i])
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,108 @@
+package engine
+
+import (
+	"abalone-go/helpers"
+	"sort"
+	"testing"
+)
+
+func sortedMoveNames(moves []Move) []string {
+	names := make([]string, 0, len(moves))
+	for _, move := range moves {
+		names = append(names, move.String())
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func TestHexSymmetriesTransformValidMoves(t *testing.T) {
+	game, err := NewGameFromLayout(BelgianDaisyLayout)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	for i := 0; i < 20; i++ {
+		if _, err := game.Move(helpers.RandIn(game.GetValidMoves())); err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+	}
+
+	helpers.AssertEqual(12, len(game.Symmetries()))
+
+	for _, s := range game.Symmetries() {
+		transformed := game.Transform(s)
+
+		expected := make([]Move, 0)
+		for _, move := range game.GetValidMoves() {
+			expected = append(expected, game.TransformMove(move, s))
+		}
+
+		helpers.AssertEqual(sortedMoveNames(expected), sortedMoveNames(transformed.GetValidMoves()))
+
+		back := transformed.Transform(s.Inverse(HexRotations))
+		helpers.AssertEqual(game.String(), back.String())
+	}
+}
+
+func TestHexSymmetryOfAMove(t *testing.T) {
+	// the bottom left corner goes to the bottom right one after a mirror
+	s := Symmetry{Reflect: true}
+	helpers.AssertEqual("A5B5", s.TransformMove(PushLine{From: Coord3D{-4, 0, 4}, Direction: TopRight}).String())
+
+	// and to the left corner after a clockwise rotation
+	s = Symmetry{Rotation: 1}
+	helpers.AssertEqual("E1E2", s.TransformMove(PushLine{From: Coord3D{-4, 0, 4}, Direction: TopRight}).String())
+}
+
+func TestHexCanonicalIsSharedBySymmetricPositions(t *testing.T) {
+	game := NewGame(&emptyGrid)
+	game.SetGrid(Coord3D{0, 0, 0}, 1)
+	game.SetGrid(Coord3D{1, -1, 0}, 1)
+	game.SetGrid(Coord3D{-2, 0, 2}, 2)
+
+	canonical, _ := game.Canonical()
+
+	for _, s := range HexSymmetries {
+		other, symmetry := game.Transform(s).Canonical()
+		helpers.AssertEqual(canonical.String(), other.String())
+		helpers.AssertEqual(canonical.String(), game.Transform(s).Transform(symmetry).String())
+	}
+}
+
+func TestSquareSymmetriesOfTicTacToe(t *testing.T) {
+	game := NewTicTacToe()
+	playPlacements(t, game, Coord2D{0, 0}, Coord2D{1, 0})
+
+	corners := make(map[string]bool)
+	for _, s := range game.Symmetries() {
+		canonical, _ := game.Transform(s).Canonical()
+		corners[canonical.showGrid()] = true
+	}
+
+	helpers.AssertEqual(8, len(game.Symmetries()))
+	helpers.AssertEqual(1, len(corners))
+
+	// a quarter turn moves the top left corner to the top right one
+	helpers.AssertEqual(Placement{At: Coord2D{2, 0}}, game.TransformMove(Placement{At: Coord2D{0, 0}}, Symmetry{Rotation: 1}))
+}
+
+func TestRectangularGridKeepsFourSymmetries(t *testing.T) {
+	game, err := NewMNKGame(4, 3, 3)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	playPlacements(t, game, Coord2D{0, 0}, Coord2D{3, 1})
+
+	helpers.AssertEqual(4, len(game.Symmetries()))
+
+	for _, s := range game.Symmetries() {
+		transformed := game.Transform(s)
+		helpers.AssertEqual(4, transformed.Width)
+		helpers.AssertEqual(10, len(transformed.GetValidMoves()))
+	}
+
+	// a quarter turn swaps the width and height
+	helpers.AssertEqual(3, game.Transform(Symmetry{Rotation: 1}).Width)
+}
@@ -0,0 +1,198 @@
+package engine
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+/**
+ * TranspositionTable remembers the results of searched positions, keyed by their hash,
+ * so that a position reached again through another move order is not searched twice.
+ *
+ * The table has a fixed number of slots, a position can only go in the slot given by its hash.
+ * When the slot holds another position, the new one replaces it if:
+ * - the old entry comes from a previous search, see NewSearch
+ * - or the new entry was searched at least as deep as the old one
+ *
+ * Slots are protected by striped locks: the table can be shared by concurrent searches.
+ */
+type TranspositionTable struct {
+	slots      []ttSlot
+	mask       uint64
+	locks      [ttLocks]sync.RWMutex
+	generation atomic.Uint32
+
+	probes   atomic.Int64
+	hits     atomic.Int64
+	stores   atomic.Int64
+	replaced atomic.Int64
+	used     atomic.Int64 // slots holding a position
+}
+
+// Bound tells how a stored score relates to the real score of the position.
+type Bound int8
+
+const (
+	BoundExact Bound = iota // the score is exact
+	BoundLower              // the real score is at least the score (the search failed high)
+	BoundUpper              // the real score is at most the score (the search failed low)
+)
+
+// TTEntry is the result of the search of a position.
+type TTEntry struct {
+	Hash     uint64
+	Depth    int // depth searched below the position
+	Bound    Bound
+	Score    float64 // score for the player to move
+	BestMove Move    // best move found, nil if none
+	Complete bool    // the search reached the end of the game in every line, the score holds at any depth
+}
+
+type ttSlot struct {
+	entry      TTEntry
+	generation uint32
+	used       bool
+}
+
+// TTStats counts the accesses to a transposition table.
+type TTStats struct {
+	Probes   int64 // lookups
+	Hits     int64 // lookups finding the position
+	Stores   int64 // entries written
+	Replaced int64 // entries written over another position
+	Used     int   // slots holding a position
+	Size     int   // slots
+}
+
+// HitRate is the share of lookups finding the position.
+func (s TTStats) HitRate() float64 {
+	if s.Probes == 0 {
+		return 0
+	}
+
+	return float64(s.Hits) / float64(s.Probes)
+}
+
+func (s TTStats) String() string {
+	return fmt.Sprintf("%d probes, hit rate %.1f%%, %d stores, %d replaced, %d/%d slots used",
+		s.Probes, s.HitRate()*100, s.Stores, s.Replaced, s.Used, s.Size)
+}
+
+const ttLocks = 256
+
+// NewTranspositionTable allocates a table of about megabytes megabytes.
+// The number of slots is rounded down to a power of two, with at least one slot.
+func NewTranspositionTable(megabytes int) *TranspositionTable {
+	capacity := megabytes * 1024 * 1024 / int(unsafe.Sizeof(ttSlot{}))
+
+	size := 1
+	for size*2 <= capacity {
+		size *= 2
+	}
+
+	return &TranspositionTable{
+		slots: make([]ttSlot, size),
+		mask:  uint64(size - 1),
+	}
+}
+
+// NewSearch starts a new search: entries of previous searches become the first to be replaced.
+func (t *TranspositionTable) NewSearch() {
+	t.generation.Add(1)
+}
+
+// Probe returns the entry stored for hash.
+func (t *TranspositionTable) Probe(hash uint64) (TTEntry, bool) {
+	t.probes.Add(1)
+
+	index := hash & t.mask
+	lock := &t.locks[index%ttLocks]
+
+	lock.RLock()
+	slot := t.slots[index]
+	lock.RUnlock()
+
+	if !slot.used || slot.entry.Hash != hash {
+		return TTEntry{}, false
+	}
+
+	t.hits.Add(1)
+	return slot.entry, true
+}
+
+// Store records entry, following the replacement policy.
+func (t *TranspositionTable) Store(entry TTEntry) {
+	index := entry.Hash & t.mask
+	lock := &t.locks[index%ttLocks]
+	generation := t.generation.Load()
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	slot := &t.slots[index]
+
+	if slot.used && slot.entry.Hash != entry.Hash {
+		if slot.generation == generation && entry.Depth < slot.entry.Depth {
+			return
+		}
+
+		t.replaced.Add(1)
+	}
+
+	// keep the best move of a shallower search of the same position
+	if slot.used && slot.entry.Hash == entry.Hash && entry.BestMove == nil {
+		entry.BestMove = slot.entry.BestMove
+	}
+
+	if !slot.used {
+		t.used.Add(1)
+	}
+
+	*slot = ttSlot{entry: entry, generation: generation, used: true}
+	t.stores.Add(1)
+}
+
+// Clear empties the table and its statistics.
+func (t *TranspositionTable) Clear() {
+	for i := range t.locks {
+		t.locks[i].Lock()
+	}
+
+	for i := range t.slots {
+		t.slots[i] = ttSlot{}
+	}
+
+	t.probes.Store(0)
+	t.hits.Store(0)
+	t.stores.Store(0)
+	t.replaced.Store(0)
+	t.used.Store(0)
+
+	for i := range t.locks {
+		t.locks[i].Unlock()
+	}
+}
+
+// Stats returns the statistics of the table since its creation or last Clear.
+func (t *TranspositionTable) Stats() TTStats {
+	return TTStats{
+		Probes:   t.probes.Load(),
+		Hits:     t.hits.Load(),
+		Stores:   t.stores.Load(),
+		Replaced: t.replaced.Load(),
+		Used:     int(t.used.Load()),
+		Size:     len(t.slots),
+	}
+}
+
+/**
+ * HashedState is a GameState with a position hash, see Game.Hash.
+ */
+type HashedState interface {
+	GameState
+	Hash() uint64
+}
+
+var _ HashedState = (*Game)(nil)
@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"abalone-go/helpers"
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestTranspositionTableStoreAndProbe(t *testing.T) {
+	table := NewTranspositionTable(1)
+
+	move := PushLine{From: Coord3D{0, 0, 0}, Direction: Right}
+	table.Store(TTEntry{Hash: 42, Depth: 3, Bound: BoundLower, Score: 12, BestMove: move})
+
+	entry, ok := table.Probe(42)
+	helpers.AssertEqual(true, ok)
+	helpers.AssertEqual(TTEntry{Hash: 42, Depth: 3, Bound: BoundLower, Score: 12, BestMove: move}, entry)
+
+	_, ok = table.Probe(43)
+	helpers.AssertEqual(false, ok)
+
+	stats := table.Stats()
+	helpers.AssertEqual(int64(2), stats.Probes)
+	helpers.AssertEqual(int64(1), stats.Hits)
+	helpers.AssertEqual(0.5, stats.HitRate())
+	helpers.AssertEqual(1, stats.Used)
+}
+
+func TestTranspositionTableReplacementPolicy(t *testing.T) {
+	table := NewTranspositionTable(0)
+	helpers.AssertEqual(1, table.Stats().Size)
+
+	// every hash goes in the single slot
+	table.Store(TTEntry{Hash: 1, Depth: 4})
+	table.Store(TTEntry{Hash: 2, Depth: 2})
+
+	_, ok := table.Probe(1)
+	helpers.AssertEqual(true, ok)
+
+	// a deeper search replaces it
+	table.Store(TTEntry{Hash: 2, Depth: 5})
+	_, ok = table.Probe(2)
+	helpers.AssertEqual(true, ok)
+
+	// and anything replaces the entries of a previous search
+	table.NewSearch()
+	table.Store(TTEntry{Hash: 3, Depth: 1})
+	_, ok = table.Probe(3)
+	helpers.AssertEqual(true, ok)
+	helpers.AssertEqual(int64(2), table.Stats().Replaced)
+	helpers.AssertEqual(1, table.Stats().Used)
+
+	table.Clear()
+	helpers.AssertEqual(0, table.Stats().Used)
+}
+
+func TestTranspositionTableConcurrentAccess(t *testing.T) {
+	table := NewTranspositionTable(1)
+	wg := sync.WaitGroup{}
+
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+
+		worker := worker
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < 1000; i++ {
+				hash := uint64(worker*1000 + i)
+				table.Store(TTEntry{Hash: hash, Depth: i})
+				table.Probe(hash)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	helpers.AssertEqual(int64(8000), table.Stats().Probes)
+}
+
+func TestAlphaBetaWithTableSearchesFewerNodes(t *testing.T) {
+	game, err := NewGameFromLayout(BelgianDaisyLayout)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	without := NewAlphaBetaPlayer(3, DefaultHeuristicEvaluator())
+	with := NewAlphaBetaPlayer(3, DefaultHeuristicEvaluator())
+	with.Table = NewTranspositionTable(16)
+
+	withoutInfo, err := without.Search(context.Background(), game)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	withInfo, err := with.Search(context.Background(), game)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(3, withInfo.Depth)
+	helpers.AssertEqual(true, withInfo.Nodes < withoutInfo.Nodes)
+	helpers.AssertEqual(true, with.Table.Stats().Hits > 0)
+}
+
+func TestTableSharedAcrossSearchesKeepsDeepening(t *testing.T) {
+	game, err := NewGameFromLayout(BelgianDaisyLayout)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	player := NewAlphaBetaPlayer(3, DefaultHeuristicEvaluator())
+	player.Table = NewTranspositionTable(16)
+
+	first, err := player.Search(context.Background(), game)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	// the positions below the root are all in the table, from depth-limited searches
+	second, err := player.Search(context.Background(), game)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(3, first.Depth)
+	helpers.AssertEqual(first.Depth, second.Depth)
+}
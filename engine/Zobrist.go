@@ -0,0 +1,90 @@
+package engine
+
+/**
+ * Zobrist hashing.
+ *
+ * Each (cell, player) pair, side to move and (player, captures) pair gets a fixed random 64 bits key.
+ * The hash of a position is the xor of the keys of its marbles, of the side to move and of the captures,
+ * so that a move only needs to xor in and out the keys of the cells it changes.
+ *
+ * Keys come from a fixed seed: hashes are stable between runs and can be stored, in opening books for instance.
+ */
+
+const zobristSeed = 0x9E3779B97F4A7C15
+
+// zobristMaxCaptures bounds the captures covered by the hash, scores are int8.
+const zobristMaxCaptures = 128
+
+var zobristCells, zobristSides, zobristCaptures = buildZobristKeys()
+
+func buildZobristKeys() ([boardSize][boardSize][MaxPlayers + 1]uint64, [MaxPlayers + 1]uint64, [MaxPlayers + 1][zobristMaxCaptures]uint64) {
+	var cells [boardSize][boardSize][MaxPlayers + 1]uint64
+	var sides [MaxPlayers + 1]uint64
+	var captures [MaxPlayers + 1][zobristMaxCaptures]uint64
+
+	state := uint64(zobristSeed)
+
+	for z := 0; z < boardSize; z++ {
+		for x := 0; x < boardSize; x++ {
+			// empty cells keep a zero key
+			for player := 1; player <= MaxPlayers; player++ {
+				cells[z][x][player] = splitMix64(&state)
+			}
+		}
+	}
+
+	for player := 1; player <= MaxPlayers; player++ {
+		sides[player] = splitMix64(&state)
+
+		// no capture keeps a zero key
+		for count := 1; count < zobristMaxCaptures; count++ {
+			captures[player][count] = splitMix64(&state)
+		}
+	}
+
+	return cells, sides, captures
+}
+
+// splitMix64 returns the next number of the SplitMix64 generator.
+func splitMix64(state *uint64) uint64 {
+	*state += 0x9E3779B97F4A7C15
+
+	z := *state
+	z = (z ^ (z >> 30)) * 0xBF58476D1CE4E5B9
+	z = (z ^ (z >> 27)) * 0x94D049BB133111EB
+
+	return z ^ (z >> 31)
+}
+
+func zobristCell(c Coord3D, v int8) uint64 {
+	return zobristCells[c.Z+BoardRadius][c.X+BoardRadius][v]
+}
+
+// Hash returns the Zobrist hash of the position: marbles, side to move and captures, not the turn.
+// Two games with the same position have the same hash.
+func (g *Game) Hash() uint64 {
+	hash := g.cellsHash ^ zobristSides[g.currentPlayer]
+
+	for player := int8(1); player <= g.rules.Players; player++ {
+		hash ^= zobristCaptures[player][g.score[player]]
+	}
+
+	return hash
+}
+
+// putCell changes a cell, keeping the hash of the marbles up to date.
+func (g *Game) putCell(c Coord3D, v int8) {
+	g.cellsHash ^= zobristCell(c, g.grid.get(c)) ^ zobristCell(c, v)
+	g.grid.set(c, v)
+}
+
+// hashCells computes the hash of all the marbles of the grid from scratch.
+func (g *Game) hashCells() uint64 {
+	hash := uint64(0)
+
+	for _, c := range boardCoords {
+		hash ^= zobristCell(c, g.grid.get(c))
+	}
+
+	return hash
+}
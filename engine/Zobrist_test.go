@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"abalone-go/helpers"
+	"testing"
+)
+
+func TestHashIsUpdatedIncrementally(t *testing.T) {
+	game, err := NewGameFromLayout(GermanDaisyLayout)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	hashes := []uint64{game.Hash()}
+
+	for i := 0; i < 200 && !game.IsOver(); i++ {
+		if _, err := game.Move(helpers.RandIn(game.GetValidMoves())); err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+
+		// same position built from scratch
+		parsed, err := ParsePosition(game.String())
+		if err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+
+		helpers.AssertEqual(parsed.Hash(), game.Hash())
+		hashes = append(hashes, game.Hash())
+	}
+
+	for i := len(hashes) - 2; i >= 0; i-- {
+		if err := game.Undo(); err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+		helpers.AssertEqual(hashes[i], game.Hash())
+	}
+}
+
+func TestHashIgnoresMoveOrder(t *testing.T) {
+	first := NewGame(&startingGrid)
+	second := NewGame(&startingGrid)
+
+	for _, notation := range []string{"A1B1", "I5H5", "A2B2", "I6H6"} {
+		if err := first.PlayNotation(notation); err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+	}
+
+	for _, notation := range []string{"A2B2", "I6H6", "A1B1", "I5H5"} {
+		if err := second.PlayNotation(notation); err != nil {
+			t.Fatalf("Error: %v", err)
+		}
+	}
+
+	helpers.AssertEqual(first.String(), second.String())
+	helpers.AssertEqual(first.Hash(), second.Hash())
+}
+
+func TestHashCoversSideToMoveAndCaptures(t *testing.T) {
+	position, err := ParsePosition("5/6/7/8/3w1wwb1/8/7/6/5 b 12 3-5")
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	otherSide, err := ParsePosition("5/6/7/8/3w1wwb1/8/7/6/5 w 12 3-5")
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	otherCaptures, err := ParsePosition("5/6/7/8/3w1wwb1/8/7/6/5 b 12 5-3")
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	otherTurn, err := ParsePosition("5/6/7/8/3w1wwb1/8/7/6/5 b 20 3-5")
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+
+	helpers.AssertEqual(false, position.Hash() == otherSide.Hash())
+	helpers.AssertEqual(false, position.Hash() == otherCaptures.Hash())
+	helpers.AssertEqual(position.Hash(), otherTurn.Hash())
+}
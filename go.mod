@@ -2,11 +2,12 @@ module abalone-go
 
 go 1.21
 
+require github.com/yaricom/goNEAT/v4 v4.0.1
+
 require (
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/sbinet/npyio v0.7.0 // indirect
 	github.com/spf13/cast v1.5.1 // indirect
-	github.com/yaricom/goNEAT/v4 v4.0.1 // indirect
 	golang.org/x/exp v0.0.0-20230321023759-10a507213a29 // indirect
 	gonum.org/v1/gonum v0.13.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
@@ -3,3 +3,10 @@ package helpers
 func Between(v int8, min int8, max int8) bool {
 	return v >= min && v <= max
 }
+
+func Abs(v int8) int8 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -14,6 +14,8 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 )
@@ -88,6 +90,16 @@ func main() {
 	var trialsCount = flag.Int("trials", 0, "The number of trials for experiment. Overrides the one set in configuration.")
 	var logLevel = flag.String("log_level", "", "The logger level to be used. Overrides the one set in configuration.")
 	var randSeed = flag.Int64("seed", 0, "The seed for random number generator")
+	var gameName = flag.String("game", "tictactoe", "The game to train on: tictactoe, gomoku, mnk or abalone.")
+	var mnk = flag.String("mnk", "7,7,4", "The width, height and line length of the mnk game.")
+	var layoutName = flag.String("layout", engine.StandardLayout, "The abalone starting layout.")
+	var layoutsPath = flag.String("layouts", "", "A file of custom abalone layouts to register.")
+	var augment = flag.Bool("augment", false, "Average the network evaluation over the symmetries of the board.")
+	var maxTurns = flag.Int("max_turns", 200, "The number of abalone moves after which a training game is a draw.")
+	var moveTime = flag.Duration("move_time", 0, "The time allowed to search players for each move, e.g. 2s. 0 for no limit.")
+	var tableSize = flag.Int("tt_size", 64, "The size in megabytes of the transposition table of each search player. 0 to disable it.")
+	var playersSpec = flag.String("players", "", "Play a single game between these players instead of training, e.g. human,random. "+
+		"Players: random, greedy, human, neat:<genome file>, alphabeta:<depth>[:<genome file>], mcts:<iterations>[:<genome file>], 0 searching until the move time.")
 
 	flag.Parse()
 
@@ -99,6 +111,29 @@ func main() {
 	}
 	rand.Seed(seed)
 
+	if len(*layoutsPath) > 0 {
+		names, err := engine.LoadLayoutsFromFile(*layoutsPath)
+		if err != nil {
+			log.Fatal("Failed to load layouts: ", err)
+		}
+		log.Println(fmt.Sprintf("Loaded layouts: %v", names))
+	}
+
+	newGame, err := buildGameFactory(*gameName, *mnk, *layoutName, *maxTurns)
+	if err != nil {
+		log.Fatal("Failed to set up the game: ", err)
+	}
+
+	if len(*playersSpec) > 0 {
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
+		defer stop()
+
+		if err := playGame(ctx, newGame, *playersSpec, *augment, *moveTime, *tableSize); err != nil {
+			log.Fatal("Failed to play the game: ", err)
+		}
+		return
+	}
+
 	// Load NEAT options
 	neatOptions, err := neat.ReadNeatOptionsFromFile(*contextPath)
 	if err != nil {
@@ -118,8 +153,8 @@ func main() {
 	hiddenNodes := make([]*network.NNode, 0)
 	outputNodes := make([]*network.NNode, 0)
 
-	// Add the 9*2 input nodes
-	for i := 0; i < 9*2; i++ {
+	// Add one input node per game feature
+	for i := 0; i < len(newGame().Features()); i++ {
 		n := network.NewNNode(nodesCount, network.InputNeuron)
 		allNodes = append(allNodes, n)
 		inputNodes = append(inputNodes, n)
@@ -191,7 +226,7 @@ func main() {
 	}
 	var generationEvaluator experiment.GenerationEvaluator
 	expt.MaxFitnessScore = 1.0
-	generationEvaluator = engine.NewAbaloneGenerationEvaluator(outDir)
+	generationEvaluator = engine.NewAbaloneGenerationEvaluator(outDir, newGame, *augment)
 
 	// prepare to execute
 	errChan := make(chan error)
@@ -257,6 +292,192 @@ func main() {
 	}
 }
 
+// buildGameFactory returns the constructor of the training games.
+func buildGameFactory(gameName string, mnk string, layoutName string, maxTurns int) (func() engine.GameState, error) {
+	switch gameName {
+	case "tictactoe":
+		return func() engine.GameState {
+			return engine.NewTicTacToe()
+		}, nil
+	case "gomoku":
+		return func() engine.GameState {
+			return engine.NewGomoku()
+		}, nil
+	case "mnk":
+		var width, height, k int
+		if _, err := fmt.Sscanf(mnk, "%d,%d,%d", &width, &height, &k); err != nil {
+			return nil, fmt.Errorf("invalid mnk game %q: %s", mnk, err)
+		}
+
+		// fail fast on an invalid board
+		if _, err := engine.NewMNKGame(width, height, k); err != nil {
+			return nil, err
+		}
+
+		return func() engine.GameState {
+			game, _ := engine.NewMNKGame(width, height, k)
+			return game
+		}, nil
+	case "abalone":
+		rules := engine.DefaultRules()
+		rules.Layout = layoutName
+		rules.MaxTurns = maxTurns
+
+		// fail fast on an unknown layout or invalid rules
+		if _, err := engine.NewGameFromRules(rules); err != nil {
+			return nil, err
+		}
+
+		return func() engine.GameState {
+			game, err := engine.NewGameFromRules(rules)
+			if err != nil {
+				panic(err)
+			}
+			return game
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown game: %s", gameName)
+	}
+}
+
+// playGame plays a single game between the players listed in specs, separated by commas.
+func playGame(ctx context.Context, newGame func() engine.GameState, specs string, augment bool, moveTime time.Duration, tableSize int) error {
+	players := make([]engine.Player, 0)
+
+	// human players share the standard input
+	human := engine.NewHumanPlayer(os.Stdin, os.Stdout)
+
+	for _, spec := range strings.Split(specs, ",") {
+		player, err := buildPlayer(strings.TrimSpace(spec), human, augment, moveTime, tableSize)
+		if err != nil {
+			return err
+		}
+		players = append(players, player)
+	}
+
+	game := newGame()
+	if abalone, ok := game.(*engine.Game); ok {
+		abalone.AddObserver(&engine.LogObserver{})
+	}
+
+	turns, err := engine.PlayGame(ctx, game, players)
+	if err != nil {
+		return err
+	}
+
+	if shown, ok := game.(interface{ Show() string }); ok {
+		fmt.Print(shown.Show())
+	}
+
+	for i, player := range players {
+		if searcher, ok := player.(*engine.AlphaBetaPlayer); ok && searcher.Table != nil && searcher.Table.Stats().Probes > 0 {
+			fmt.Printf("Player %d transposition table: %v\n", i+1, searcher.Table.Stats())
+		}
+	}
+
+	switch game.Winner() {
+	case engine.Draw:
+		fmt.Printf("Draw after %d moves\n", turns)
+	case engine.NoWinner:
+		fmt.Printf("No more valid moves after %d moves\n", turns)
+	default:
+		fmt.Printf("Player %d won after %d moves\n", game.Winner(), turns)
+	}
+
+	return nil
+}
+
+// buildPlayer returns the player described by spec, like "random" or "neat:out/champion".
+// Search players get moveTime for each move and a transposition table of tableSize megabytes.
+func buildPlayer(spec string, human *engine.HumanPlayer, augment bool, moveTime time.Duration, tableSize int) (engine.Player, error) {
+	name, arg, _ := strings.Cut(spec, ":")
+
+	switch name {
+	case "random":
+		return &engine.RandomPlayer{}, nil
+	case "greedy":
+		return &engine.GreedyPlayer{}, nil
+	case "human":
+		return human, nil
+	case "neat":
+		return loadNeatPlayer(arg, augment)
+	case "alphabeta":
+		depthArg, genomePath, _ := strings.Cut(arg, ":")
+
+		// depth 0 searches as deep as the move time allows
+		depth, err := strconv.Atoi(depthArg)
+		if err != nil || depth < 0 || (depth == 0 && moveTime == 0) {
+			return nil, fmt.Errorf("invalid search depth in %q", spec)
+		}
+
+		var evaluator engine.Evaluator = engine.DefaultHeuristicEvaluator()
+		if genomePath != "" {
+			if evaluator, err = loadNeatPlayer(genomePath, augment); err != nil {
+				return nil, err
+			}
+		}
+
+		player := engine.NewAlphaBetaPlayer(depth, evaluator)
+		player.TimeBudget = moveTime
+		player.Verbose = true
+
+		if tableSize > 0 {
+			player.Table = engine.NewTranspositionTable(tableSize)
+		}
+
+		return player, nil
+	case "mcts":
+		iterationsArg, genomePath, _ := strings.Cut(arg, ":")
+
+		// 0 iterations searches until the move time
+		iterations, err := strconv.Atoi(iterationsArg)
+		if err != nil || iterations < 0 || (iterations == 0 && moveTime == 0) {
+			return nil, fmt.Errorf("invalid iterations in %q", spec)
+		}
+
+		var rollout engine.Player = &engine.RandomPlayer{}
+		if genomePath != "" {
+			if rollout, err = loadNeatPlayer(genomePath, augment); err != nil {
+				return nil, err
+			}
+		}
+
+		player := engine.NewMCTSPlayer(iterations, rollout)
+		player.TimeBudget = moveTime
+		player.Verbose = true
+
+		return player, nil
+	default:
+		return nil, fmt.Errorf("unknown player: %s", spec)
+	}
+}
+
+// loadNeatPlayer builds a NEAT player from a genome file written by the trainer.
+func loadNeatPlayer(genomePath string, augment bool) (*engine.NeatPlayer, error) {
+	reader, err := genetics.NewGenomeReaderFromFile(genomePath)
+	if err != nil {
+		return nil, err
+	}
+
+	genome, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	phenotype, err := genome.Genesis(genome.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	player, err := engine.NewNeatPlayer(phenotype)
+	if err != nil {
+		return nil, err
+	}
+	player.Augment = augment
+
+	return player, nil
+}
+
 type myObserver struct {
 }
 